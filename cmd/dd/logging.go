@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
+)
+
+// Local log formats, selected with --log-format
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logging is the logging of a run: local logs written to stderr or a file
+// and, unless remote logs are turned off, logs sent to the configured sink
+type logging struct {
+	file      *os.File
+	ddHandler *ddapi.DatadogHandler
+}
+
+// setupLogging makes the default logger honor the log level, format, file
+// and remote logging settings of cfg. Every log is redacted. Remote logs are
+// only set up, and so only sent, if cfg.NoRemoteLogs is false.
+func setupLogging(cfg *config.Config, redactor *ddapi.Redactor, env string) (*logging, error) {
+	level, err := parseLogLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &logging{}
+	var out io.Writer = console.Stderr
+	if cfg.LogFile != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.LogFile), 0700); err != nil {
+			return nil, fmt.Errorf("error creating log file directory: %w", err)
+		}
+		l.file, err = os.OpenFile(cfg.LogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening log file: %w", err)
+		}
+		out = l.file
+	}
+
+	handler, err := newLocalHandler(out, cfg.LogFormat, level)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	ddapi.SetHTTPTraceOutput(newTraceOutput(out, cfg.LogFormat))
+
+	if !cfg.NoRemoteLogs {
+		l.ddHandler, err = newLogHandler(cfg, level, handler, env)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		if l.ddHandler != nil {
+			handler = l.ddHandler
+		}
+	}
+
+	slog.SetDefault(slog.New(ddapi.NewRedactingHandler(handler, redactor)))
+	if l.ddHandler != nil {
+		slog.Info("Datadog logging enabled",
+			"environment", env,
+			"service", "datadog-cli",
+			"level", level.String())
+	}
+	return l, nil
+}
+
+// Close waits for pending remote logs to be sent and closes the log file. It
+// must also run before os.Exit.
+func (l *logging) Close() {
+	if l == nil {
+		return
+	}
+
+	if l.ddHandler != nil {
+		if err := l.ddHandler.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing Datadog log handler: %v\n", err)
+		}
+		l.ddHandler = nil
+	}
+	if l.file != nil {
+		ddapi.SetHTTPTraceOutput(console.Stderr)
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+// newLogHandler creates the Datadog log handler for the sink selected in cfg.
+// It returns nil if there is no sink to send logs to.
+func newLogHandler(cfg *config.Config, level slog.Level, fallback slog.Handler, env string) (*ddapi.DatadogHandler, error) {
+	sink, err := ddapi.NewSink(cfg)
+	if err != nil || sink == nil {
+		return nil, err
+	}
+
+	return ddapi.NewDatadogHandler(cfg, &ddapi.DatadogHandlerOptions{
+		MinLevel:    level,
+		Fallback:    fallback,
+		Service:     "datadog-cli",
+		Environment: env,
+		Sink:        sink,
+		SpoolDir:    cfg.LogSpoolDir,
+		SampleRates: ddapi.DefaultSampleRates,
+		RateLimit:   ddapi.DefaultRateLimit,
+		RateBurst:   ddapi.DefaultRateBurst,
+	}), nil
+}
+
+// parseLogLevel parses a log level: debug, info, warn or error. An empty
+// level is info.
+func parseLogLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", value)
+	}
+	return level, nil
+}
+
+// newLocalHandler creates the handler of local logs in the given format
+func newLocalHandler(out io.Writer, format string, level slog.Level) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", logFormatText:
+		return slog.NewTextHandler(out, opts), nil
+	case logFormatJSON:
+		return slog.NewJSONHandler(out, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want %s or %s)", format, logFormatText, logFormatJSON)
+	}
+}
+
+// newTraceOutput returns where --trace-http exchanges are written: next to
+// local logs, as they are in text format, or as one JSON record each in
+// json format
+func newTraceOutput(out io.Writer, format string) io.Writer {
+	if strings.ToLower(format) == logFormatJSON {
+		return traceRecords{handler: slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})}
+	}
+	return out
+}
+
+// traceRecords writes each HTTP exchange as a JSON log record
+type traceRecords struct {
+	handler slog.Handler
+}
+
+// Write implements io.Writer. Each write is one exchange.
+func (w traceRecords) Write(p []byte) (int, error) {
+	record := slog.NewRecord(time.Now(), slog.LevelDebug, "HTTP trace", 0)
+	record.AddAttrs(slog.String("trace", strings.TrimRight(string(p), "\n")))
+	if err := w.handler.Handle(context.Background(), record); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/padawandba/datadog-cli/internal/audit"
+	"github.com/padawandba/datadog-cli/internal/downtimes"
 	"github.com/padawandba/datadog-cli/internal/hosts"
 	"github.com/padawandba/datadog-cli/internal/monitors"
 	"github.com/padawandba/datadog-cli/internal/platform/config"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
 	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
 	"github.com/padawandba/datadog-cli/internal/tags"
+	"github.com/padawandba/datadog-cli/internal/ui"
 	"github.com/urfave/cli/v2"
 )
 
@@ -22,34 +28,66 @@ func validateConfig(cfg *config.Config) error {
 	return config.Validate(cfg)
 }
 
-func main() {
-	// Set up signal handling for graceful shutdown
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+// localCommands are the command groups that never call the Datadog API
+var localCommands = []string{"audit"}
 
-	// Check if help is requested
-	isHelp := false
-	for _, arg := range os.Args {
-		if arg == "--help" || arg == "-h" || arg == "help" {
-			isHelp = true
-			break
+// isLocalCommand reports whether a command, as returned by commandName,
+// belongs to a group that never calls the Datadog API
+func isLocalCommand(command string) bool {
+	group, _, _ := strings.Cut(command, " ")
+	for _, name := range localCommands {
+		if group == name {
+			return true
 		}
 	}
+	return false
+}
 
-	// Set up initial basic logging
-	var logLevel slog.Level
-	if os.Getenv("DD_DEBUG") != "" {
-		logLevel = slog.LevelDebug
-	} else {
-		logLevel = slog.LevelInfo
+// helpRequested reports whether args, the arguments after the global flags,
+// ask for help: the help command or a -h or --help flag before any "--"
+func helpRequested(args []string) bool {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case arg == "-h" || arg == "--help":
+			return true
+		case i == 0 && (arg == "help" || arg == "h"):
+			return true
+		}
 	}
-	
-	// Start with a basic stderr logger
-	basicHandler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: logLevel,
-	})
-	logger := slog.New(basicHandler)
-	slog.SetDefault(logger)
+	return false
+}
+
+// commandName returns the command and subcommand names at the start of args,
+// e.g., "hosts list", skipping their flags and arguments
+func commandName(commands []*cli.Command, args []string) string {
+	names := make([]string, 0, 2)
+	for _, arg := range args {
+		var found *cli.Command
+		for _, cmd := range commands {
+			if cmd.HasName(arg) {
+				found = cmd
+				break
+			}
+		}
+		if found == nil {
+			break
+		}
+		names = append(names, found.Name)
+		commands = found.Subcommands
+	}
+	return strings.Join(names, " ")
+}
+
+func main() {
+	// Set up signal handling for graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Log to stderr until the logging flags are known
+	basicHandler := slog.NewTextHandler(os.Stderr, nil)
+	slog.SetDefault(slog.New(basicHandler))
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -57,41 +95,23 @@ func main() {
 		slog.Error("Failed to load configuration", "error", err)
 		os.Exit(1)
 	}
-
-	// Set up Datadog logging if not in help mode and we have API credentials
-	var ddHandler *ddapi.DatadogHandler
-	if !isHelp && cfg.APIKey != "" && cfg.AppKey != "" {
-		// Get environment from env var or default to "dev"
-		env := os.Getenv("DD_ENV")
-		if env == "" {
-			env = "dev"
-		}
-		
-		// Create Datadog handler with fallback to stderr
-		ddHandler = ddapi.NewDatadogHandler(cfg, &ddapi.DatadogHandlerOptions{
-			MinLevel:    logLevel,
-			Fallback:    basicHandler,
-			Service:     "datadog-cli",
-			Environment: env,
-		})
-		
-		// Set as default logger
-		logger = slog.New(ddHandler)
-		slog.SetDefault(logger)
-		
-		// Ensure handler is closed on exit
-		defer func() {
-			if err := ddHandler.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error closing Datadog log handler: %v\n", err)
-			}
-		}()
-		
-		slog.Info("Datadog logging enabled", 
-			"environment", env,
-			"service", "datadog-cli",
-			"level", logLevel.String())
+	
+	// Mask secrets in everything logged, both on stderr and in Datadog
+	redactor, err := ddapi.NewRedactor(cfg)
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
 	}
+	slog.SetDefault(slog.New(ddapi.NewRedactingHandler(basicHandler, redactor)))
 
+	// Logging is set up in Before, once the logging flags are parsed
+	var logs *logging
+	closeLogs := func() {
+		logs.Close()
+		logs = nil
+	}
+	defer closeLogs()
+	
 	app := &cli.App{
 		Name:  "dd",
 		Usage: "Datadog administration CLI tool",
@@ -116,12 +136,98 @@ func main() {
 				Name:    "output",
 				Aliases: []string{"o"},
 				Value:   "table",
-				Usage:   "Output format (table, json, yaml)",
+				Usage:   "Output format (table, wide, json, yaml, csv, tsv, ndjson, markdown, html, go-template=TEMPLATE, jsonpath=TEMPLATE, custom-columns=SPEC)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "columns",
+				Usage: "Columns to show in table and delimited output, by JSON field name (e.g., name,up,is_muted)",
+			},
+			&cli.StringFlag{
+				Name:  "template-file",
+				Usage: "Go template file to render the output with (implies -o go-template)",
+			},
+			&cli.StringFlag{
+				Name:  "sort-by",
+				Usage: "Sort list output by JSON field names (e.g., last_reported_at or status,name)",
+			},
+			&cli.BoolFlag{
+				Name:  "reverse",
+				Usage: "Reverse the order of list output",
+			},
+			&cli.StringFlag{
+				Name:  "where",
+				Usage: "Filter list output with an expression over JSON field names (e.g., 'up == false && is_muted == false')",
+			},
+			&cli.BoolFlag{
+				Name:  "json-envelope",
+				Usage: "Wrap JSON and YAML output in a versioned {apiVersion, kind, items, metadata} envelope",
+			},
+			&cli.DurationFlag{
+				Name:  "watch",
+				Usage: "Re-run list commands at this interval and highlight changed rows (e.g., 15s)",
+			},
+			&cli.BoolFlag{
+				Name:  "no-color",
+				Usage: "Disable colored table output (also disabled by the NO_COLOR env var)",
+			},
+			&cli.StringFlag{
+				Name:    "log-sink",
+				EnvVars: []string{"DD_LOG_SINK"},
+				Usage:   "Where to send logs: datadog (logs intake), otlp (OTLP/HTTP endpoint) or file (local JSONL file)",
+			},
+			&cli.StringFlag{
+				Name:    "log-sink-path",
+				EnvVars: []string{"DD_LOG_SINK_PATH"},
+				Usage:   "File the file log sink appends to (default: dd/logs.jsonl in the user cache directory)",
+			},
+			&cli.StringFlag{
+				Name:  "log-otlp-endpoint",
+				Usage: "OTLP/HTTP logs URL for the otlp log sink (default: OTEL_EXPORTER_OTLP_LOGS_ENDPOINT or http://localhost:4318/v1/logs)",
+			},
+			&cli.StringFlag{
+				Name:    "metrics",
+				EnvVars: []string{"DD_CLI_METRICS"},
+				Usage:   "Send command and API call metrics: dogstatsd (local agent), api (Datadog API) or off",
+			},
+			&cli.BoolFlag{
+				Name:    "traces",
+				EnvVars: []string{"DD_CLI_TRACES"},
+				Usage:   "Send a trace of each command and its API calls to the local Datadog Agent",
 			},
 			&cli.BoolFlag{
 				Name:    "debug",
 				EnvVars: []string{"DD_DEBUG"},
-				Usage:   "Enable debug logging",
+				Usage:   "Enable debug logging, like --log-level debug (implies --trace-http)",
+			},
+			&cli.StringFlag{
+				Name:    "log-level",
+				EnvVars: []string{"DD_LOG_LEVEL"},
+				Usage:   "Minimum level of logs: debug, info, warn or error (default: info)",
+			},
+			&cli.StringFlag{
+				Name:    "log-format",
+				EnvVars: []string{"DD_LOG_FORMAT"},
+				Usage:   "Format of local logs: text or json (default: text)",
+			},
+			&cli.StringFlag{
+				Name:    "log-file",
+				EnvVars: []string{"DD_LOG_FILE"},
+				Usage:   "Write local logs to this file instead of stderr",
+			},
+			&cli.BoolFlag{
+				Name:    "no-remote-logs",
+				EnvVars: []string{"DD_NO_REMOTE_LOGS"},
+				Usage:   "Do not send logs to Datadog or another log sink",
+			},
+			&cli.BoolFlag{
+				Name:    "trace-http",
+				EnvVars: []string{"DD_TRACE_HTTP"},
+				Usage:   "Write each API request and response, redacted, with local logs and a curl command to repeat it",
+			},
+			&cli.IntFlag{
+				Name:    "http-body-limit",
+				EnvVars: []string{"DD_HTTP_BODY_LIMIT"},
+				Usage:   "Bytes of each request and response body written by --trace-http (default 4096)",
 			},
 			&cli.StringFlag{
 				Name:    "env",
@@ -131,8 +237,8 @@ func main() {
 			},
 		},
 		Before: func(c *cli.Context) error {
-			// Skip validation if just showing help
-			if isHelp {
+			// Skip validation and logging setup if just showing help
+			if helpRequested(c.Args().Slice()) {
 				return nil
 			}
 			
@@ -149,34 +255,87 @@ func main() {
 			if output := c.String("output"); output != "" {
 				cfg.Output = output
 			}
+			cfg.TemplateFile = c.String("template-file")
+			cfg.Columns = c.StringSlice("columns")
+			cfg.SortBy = c.String("sort-by")
+			cfg.Reverse = c.Bool("reverse")
+			cfg.Where = c.String("where")
+			cfg.NoColor = c.Bool("no-color")
+			cfg.Watch = c.Duration("watch")
+			cfg.JSONEnvelope = c.Bool("json-envelope")
 			
-			// Update log level if debug flag is set
-			if c.Bool("debug") && logLevel != slog.LevelDebug {
-				// Update log level for existing handlers
-				newLevel := slog.LevelDebug
-				
-				if ddHandler != nil {
-					// Create a new Datadog handler with debug level
-					env := c.String("env")
-					ddHandler = ddapi.NewDatadogHandler(cfg, &ddapi.DatadogHandlerOptions{
-						MinLevel:    newLevel,
-						Fallback:    slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: newLevel}),
-						Service:     "datadog-cli",
-						Environment: env,
-					})
-					
-					logger = slog.New(ddHandler)
-					slog.SetDefault(logger)
-				} else {
-					// Just update the stderr handler
-					handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-						Level: newLevel,
-					})
-					logger := slog.New(handler)
-					slog.SetDefault(logger)
-				}
-				
-				slog.Debug("Debug logging enabled")
+			// Reject unknown output formats, malformed templates and filters
+			// before any API call is made
+			if _, err := console.NewFormatterFromConfig(cfg); err != nil {
+				return err
+			}
+			
+			// Apply log sink flags
+			if sink := c.String("log-sink"); sink != "" {
+				cfg.LogSink = sink
+			}
+			if sinkPath := c.String("log-sink-path"); sinkPath != "" {
+				cfg.LogSinkPath = sinkPath
+			}
+			if endpoint := c.String("log-otlp-endpoint"); endpoint != "" {
+				cfg.LogOTLPEndpoint = endpoint
+			}
+			
+			// Apply logging flags; --debug is short for --log-level debug
+			if level := c.String("log-level"); level != "" {
+				cfg.LogLevel = level
+			}
+			if c.Bool("debug") {
+				cfg.LogLevel = "debug"
+			}
+			if format := c.String("log-format"); format != "" {
+				cfg.LogFormat = format
+			}
+			if logFile := c.String("log-file"); logFile != "" {
+				cfg.LogFile = logFile
+			}
+			if c.Bool("no-remote-logs") {
+				cfg.NoRemoteLogs = true
+			}
+			
+			// Set up logging once, before anything is logged or sent
+			var err error
+			if logs, err = setupLogging(cfg, redactor, c.String("env")); err != nil {
+				return err
+			}
+			slog.Info("Executing command", "args", os.Args)
+			
+			// Write API requests and responses to stderr when tracing or debugging
+			cfg.TraceHTTP = c.Bool("trace-http") || c.Bool("debug")
+			if c.IsSet("http-body-limit") {
+				cfg.HTTPBodyLimit = c.Int("http-body-limit")
+			}
+			
+			// Emit metrics and traces for the command if enabled
+			if c.IsSet("metrics") {
+				cfg.Metrics = c.String("metrics")
+			}
+			if c.IsSet("traces") {
+				cfg.Traces = c.Bool("traces")
+			}
+			telemetry, err := ddapi.NewTelemetry(cfg, &ddapi.TelemetryOptions{
+				Metrics:       cfg.Metrics,
+				DogStatsDAddr: cfg.DogStatsDAddr,
+				Traces:        cfg.Traces,
+				TraceAgentURL: cfg.TraceAgentURL,
+				Service:       "datadog-cli",
+				Environment:   c.String("env"),
+			})
+			if err != nil {
+				return err
+			}
+			command := commandName(c.App.Commands, c.Args().Slice())
+			telemetry.StartCommand(command)
+			ddapi.SetDefaultTelemetry(telemetry)
+			
+			// Commands that only read local files need no API keys
+			if isLocalCommand(command) {
+				return nil
 			}
 			
 			// Validate required configuration
@@ -184,7 +343,7 @@ func main() {
 		},
 		// Custom command not found handler to show help
 		CommandNotFound: func(c *cli.Context, command string) {
-			if isHelp {
+			if helpRequested(c.Args().Slice()) {
 				cli.ShowAppHelp(c)
 				return
 			}
@@ -192,7 +351,7 @@ func main() {
 		},
 		// Custom usage error handler to show help
 		OnUsageError: func(c *cli.Context, err error, isSubcommand bool) error {
-			if isHelp {
+			if helpRequested(c.Args().Slice()) {
 				if isSubcommand {
 					return cli.ShowSubcommandHelp(c)
 				}
@@ -203,33 +362,45 @@ func main() {
 		},
 	}
 
-	// Initialize the Datadog client
-	var client *datadog.APIClient
-	var apiCtx context.Context
+	// Initialize the Datadog client; it makes no request until a command runs
+	client, apiCtx := ddapi.NewClient(ctx, cfg)
 	
-	// Create a basic client for help mode
-	configuration := datadog.NewConfiguration()
-	client = datadog.NewAPIClient(configuration)
-	apiCtx = ctx
+	// Ensure client resources are cleaned up on exit
+	defer ddapi.CleanupContext(apiCtx)
 	
-	// Only initialize the real client if not in help mode
-	if !isHelp {
-		client, apiCtx = ddapi.NewClient(cfg)
-		
-		// Ensure client resources are cleaned up on exit
-		defer ddapi.CleanupContext(apiCtx)
+	// Record the changes made by commands in the audit log
+	auditPath := cfg.AuditLog
+	if auditPath == "" {
+		auditPath, err = audit.DefaultPath()
+		if err != nil {
+			slog.Warn("Audit log disabled", "error", err)
+		}
+	}
+	auditLog := audit.NewLog(auditPath)
+	if auditPath != "" {
+		var eventsClient *datadog.APIClient
+		if cfg.AuditEvents {
+			eventsClient = client
+		}
+		apiCtx = audit.NewContext(apiCtx, audit.NewRecorder(auditLog, cfg, eventsClient))
 	}
 
 	// Create command groups
 	hostsCmd := hosts.NewCommands(client, apiCtx, cfg)
 	tagsCmd := tags.NewCommands(client, apiCtx, cfg)
 	monitorsCmd := monitors.NewCommands(client, apiCtx, cfg)
+	downtimesCmd := downtimes.NewCommands(client, apiCtx, cfg)
+	uiCmd := ui.NewCommands(client, apiCtx, cfg)
+	auditCmd := audit.NewCommands(auditLog, cfg)
 	
 	// Add commands to the application
 	app.Commands = []*cli.Command{
 		hostsCmd,
 		tagsCmd,
 		monitorsCmd,
+		downtimesCmd,
+		uiCmd,
+		auditCmd,
 	}
 
 	// Override the default help flag
@@ -239,13 +410,40 @@ func main() {
 		Usage:   "Show help",
 	}
 
-	// Log command execution
-	slog.Info("Executing command", "args", os.Args)
+	// closeTelemetry records the command's outcome and sends pending metrics and traces
+	closeTelemetry := func(err error) {
+		telemetry := ddapi.DefaultTelemetry()
+		telemetry.FinishCommand(err)
+		if err := telemetry.Close(); err != nil {
+			slog.Warn("Failed to send metrics and traces", "error", err)
+		}
+	}
 	
 	if err := app.RunContext(ctx, os.Args); err != nil {
-		slog.Error("Application error", "error", err)
-		os.Exit(1)
+		errCtx := ddapi.DefaultTelemetry().Context(ctx)
+		
+		if format, _ := console.ParseOutputFormat(cfg.Output); format == console.JSONFormat || format == console.NDJSONFormat {
+			// Automation that selected JSON output gets the error as a single
+			// JSON line, the only error written to stderr: the log record still
+			// goes to the log file and sink, but not to the terminal
+			release := console.Stderr.Hold()
+			slog.ErrorContext(errCtx, "Application error", "error", err)
+			release()
+			if data, err := json.Marshal(ddapi.NewErrorReport(err)); err == nil {
+				fmt.Fprintln(os.Stderr, string(data))
+			}
+		} else {
+			slog.ErrorContext(errCtx, "Application error", "error", err)
+			if cfg.LogFile != "" {
+				// The log went to the file; the user still needs to see the error
+				fmt.Fprintf(os.Stderr, "Error: %s\n", redactor.String(err.Error()))
+			}
+		}
+		closeTelemetry(err)
+		closeLogs()
+		os.Exit(ddapi.ExitCode(err))
 	}
 	
-	slog.Info("Command completed successfully")
+	slog.InfoContext(ddapi.DefaultTelemetry().Context(ctx), "Command completed successfully")
+	closeTelemetry(nil)
 }
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommands returns the audit command group
+func NewCommands(log *Log, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "audit",
+		Usage: "Show the local audit log of changes made with the CLI",
+		Subcommands: []*cli.Command{
+			showCommand(log, cfg),
+			verifyCommand(log),
+		},
+	}
+}
+
+// showCommand returns the command to show audit entries
+func showCommand(log *Log, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "show",
+		Usage: "Show audited actions, oldest first",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only show actions since a duration ago (e.g., 12h, 7d) or an RFC 3339 time",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			since, err := parseSince(c.String("since"), time.Now())
+			if err != nil {
+				return err
+			}
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			entries, err := log.Read(since)
+			if err != nil {
+				return fmt.Errorf("failed to read audit log: %w", err)
+			}
+			return formatter.Format(entries)
+		},
+	}
+}
+
+// verifyCommand returns the command to verify the audit log hash chain
+func verifyCommand(log *Log) *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Check that no audit entry was changed or removed",
+		Action: func(c *cli.Context) error {
+			count, err := log.Verify()
+			if err != nil {
+				return err
+			}
+			
+			fmt.Printf("Verified %d audit entries in %s\n", count, log.Path())
+			return nil
+		},
+	}
+}
+
+// parseSince parses a --since value: a Go duration, a number of days such as
+// "7d", or an RFC 3339 time. An empty value returns the zero time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration such as 12h or 7d, or an RFC 3339 time", value)
+}
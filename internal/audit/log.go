@@ -0,0 +1,321 @@
+// Package audit keeps a tamper-evident local record of the changes the CLI
+// makes: host mutes, tag edits, monitor mutes and downtimes. Entries are
+// appended to a JSONL file in which each entry carries the hash of the one
+// before it, so that editing or removing an entry breaks the chain.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Results recorded for an action
+const (
+	ResultSuccess = "success"
+	ResultFailed  = "failed"
+)
+
+// Entry is a single audited action
+type Entry struct {
+	Time     time.Time   `json:"time"`
+	Operator string      `json:"operator"`
+	Profile  string      `json:"profile"`
+	Site     string      `json:"site"`
+	Action   string      `json:"action"`
+	Target   string      `json:"target"`
+	Before   interface{} `json:"before,omitempty"`
+	After    interface{} `json:"after,omitempty"`
+	Result   string      `json:"result"`
+	Error    string      `json:"error,omitempty"`
+	PrevHash string      `json:"prev_hash"`
+	
+	// Hash must stay the last field: it is computed over the encoded entry
+	// without it, see encodeEntry
+	Hash string `json:"hash,omitempty"`
+}
+
+// DefaultColumns returns the columns shown in table output unless -o wide is used
+func (Entry) DefaultColumns() []string {
+	return []string{"time", "operator", "action", "target", "result"}
+}
+
+// Kind names audit entries in JSON envelopes and schemas
+func (Entry) Kind() string {
+	return "AuditEntry"
+}
+
+// ErrChainBroken is returned when an entry does not match its hash or the
+// hash of the entry before it
+var ErrChainBroken = errors.New("audit log hash chain is broken")
+
+const (
+	// lockTimeout bounds how long Append waits for another run to finish writing
+	lockTimeout = 5 * time.Second
+	
+	// staleLock is the age after which a lock file left by a crashed run is removed
+	staleLock = 30 * time.Second
+	
+	// tailChunk is the size of the first read from the end of the log when
+	// looking for the last entry
+	tailChunk = 64 * 1024
+)
+
+// Log is an append-only, hash-chained JSONL audit log
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultPath returns the default audit log path, next to the configuration file
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "dd", "audit.jsonl"), nil
+}
+
+// NewLog creates a Log for the file at path. The file and its directory are
+// created by the first Append.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file the log is written to
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append chains the entry to the last one in the log and writes it. The
+// entry's PrevHash and Hash are set.
+func (l *Log) Append(entry *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("error creating audit log directory: %w", err)
+	}
+	
+	// Other runs may append at the same time; the lock keeps the chain linear
+	unlock, err := lockFile(l.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	
+	prevHash, err := lastHash(l.path)
+	if err != nil {
+		return err
+	}
+	
+	entry.PrevHash = prevHash
+	entry.Hash = ""
+	line, hash, err := encodeEntry(entry)
+	if err != nil {
+		return err
+	}
+	
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening audit log: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+	
+	entry.Hash = hash
+	return nil
+}
+
+// Read returns the entries recorded at or after since, oldest first. A zero
+// since returns every entry. A missing log has no entries.
+func (l *Log) Read(since time.Time) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	err := l.scan(func(lineNo int, line []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("audit log line %d: %w", lineNo, err)
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// Verify checks every entry against its hash and the hash of the entry
+// before it. It returns the number of entries verified, and an error wrapping
+// ErrChainBroken for the first entry that does not match.
+func (l *Log) Verify() (int, error) {
+	count := 0
+	prevHash := ""
+	err := l.scan(func(lineNo int, line []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrChainBroken, lineNo, err)
+		}
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("%w: line %d does not follow the entry before it", ErrChainBroken, lineNo)
+		}
+		
+		body, ok := withoutHash(line, entry.Hash)
+		if !ok || hashEntry(body) != entry.Hash {
+			return fmt.Errorf("%w: line %d does not match its hash", ErrChainBroken, lineNo)
+		}
+		
+		prevHash = entry.Hash
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// scan calls fn for each non-empty line of the log
+func (l *Log) scan(fn func(lineNo int, line []byte) error) error {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening audit log: %w", err)
+	}
+	defer file.Close()
+	
+	reader := bufio.NewReader(file)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if err := fn(lineNo, line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading audit log: %w", err)
+		}
+	}
+}
+
+// encodeEntry encodes an entry without its hash, hashes the encoding chained
+// to the previous hash, and returns the line with the hash appended as the
+// last field
+func encodeEntry(entry *Entry) ([]byte, string, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding audit entry: %w", err)
+	}
+	
+	hash := hashEntry(body)
+	line := append(body[:len(body)-1:len(body)-1], hashSuffix(hash)...)
+	return line, hash, nil
+}
+
+// hashEntry hashes an entry encoded without its hash. The entry includes the
+// previous hash, which chains it to the entry before it.
+func hashEntry(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashSuffix is the end of an encoded entry carrying its hash
+func hashSuffix(hash string) []byte {
+	return []byte(`,"hash":"` + hash + `"}`)
+}
+
+// withoutHash returns a line as it was encoded before its hash was appended
+func withoutHash(line []byte, hash string) ([]byte, bool) {
+	suffix := hashSuffix(hash)
+	if hash == "" || !bytes.HasSuffix(line, suffix) {
+		return nil, false
+	}
+	body := append([]byte{}, line[:len(line)-len(suffix)]...)
+	return append(body, '}'), true
+}
+
+// lastHash returns the hash of the last entry in the log, or "" if the log
+// is empty or missing
+func lastHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error opening audit log: %w", err)
+	}
+	defer file.Close()
+	
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("error reading audit log: %w", err)
+	}
+	
+	// Read ever larger chunks from the end until the last line is complete
+	size := info.Size()
+	for chunk := int64(tailChunk); ; chunk *= 2 {
+		if chunk > size {
+			chunk = size
+		}
+		buf := make([]byte, chunk)
+		if _, err := file.ReadAt(buf, size-chunk); err != nil && err != io.EOF {
+			return "", fmt.Errorf("error reading audit log: %w", err)
+		}
+		
+		buf = bytes.TrimRight(buf, "\r\n\t ")
+		if len(buf) == 0 {
+			return "", nil
+		}
+		start := bytes.LastIndexByte(buf, '\n')
+		if start < 0 && chunk < size {
+			continue
+		}
+		
+		var entry Entry
+		if err := json.Unmarshal(buf[start+1:], &entry); err != nil {
+			return "", fmt.Errorf("%w: last entry: %v", ErrChainBroken, err)
+		}
+		return entry.Hash, nil
+	}
+}
+
+// lockFile takes an exclusive lock by creating path, waiting up to
+// lockTimeout for another holder and removing locks left by crashed runs.
+// It returns the function releasing the lock.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("error locking audit log: %w", err)
+		}
+		
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for audit log lock %s", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
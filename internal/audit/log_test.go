@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLog_AppendAndVerify(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "dd", "audit.jsonl"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := &Entry{
+				Time:   time.Now().UTC(),
+				Action: "monitor.mute",
+				Target: "monitor:42",
+				Before: map[string]interface{}{"silenced": map[string]int64{}},
+				After:  map[string]interface{}{"silenced": map[string]int64{"*": 1700000000}},
+				Result: ResultSuccess,
+			}
+			if err := log.Append(entry); err != nil {
+				t.Errorf("Append() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	count, err := log.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if count != 10 {
+		t.Errorf("Verify() = %d entries, want 10", count)
+	}
+
+	entries, err := log.Read(time.Time{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if entries[0].PrevHash != "" {
+		t.Errorf("first entry prev_hash = %q, want empty", entries[0].PrevHash)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].PrevHash != entries[i-1].Hash {
+			t.Errorf("entry %d prev_hash = %q, want %q", i, entries[i].PrevHash, entries[i-1].Hash)
+		}
+	}
+}
+
+func TestLog_VerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines [][]byte) [][]byte
+	}{
+		{
+			name: "edited entry",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte("alice"), []byte("mallory"), 1)
+				return lines
+			},
+		},
+		{
+			name: "removed entry",
+			tamper: func(lines [][]byte) [][]byte {
+				return append(lines[:1], lines[2:]...)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			log := NewLog(path)
+			for _, target := range []string{"host:a", "host:b", "host:c"} {
+				entry := &Entry{Time: time.Now().UTC(), Operator: "alice", Action: "host.mute", Target: target, Result: ResultSuccess}
+				if err := log.Append(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(bytes.Split(bytes.TrimSpace(data), []byte("\n")))
+			if err := os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := log.Verify(); !errors.Is(err, ErrChainBroken) {
+				t.Errorf("Verify() error = %v, want ErrChainBroken", err)
+			}
+		})
+	}
+}
+
+func TestLog_ReadSince(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	now := time.Now().UTC()
+	for _, age := range []time.Duration{10 * 24 * time.Hour, 2 * 24 * time.Hour, time.Hour} {
+		if err := log.Append(&Entry{Time: now.Add(-age), Action: "host.unmute", Result: ResultSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	since, err := parseSince("7d", now)
+	if err != nil {
+		t.Fatalf("parseSince() error = %v", err)
+	}
+	entries, err := log.Read(since)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Read(7d ago) = %d entries, want 2", len(entries))
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "2024-05-01T00:00:00Z", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
+)
+
+// maxEventText is the longest event text the events API accepts
+const maxEventText = 4000
+
+// Recorder records actions in the audit log and, when enabled, forwards
+// them to Datadog as events. A nil Recorder records nothing.
+type Recorder struct {
+	log       *Log
+	cfg       *config.Config
+	operator  string
+	apiClient *datadog.APIClient
+}
+
+// NewRecorder creates a Recorder writing to log. When apiClient is not nil,
+// each entry is also posted as a Datadog event.
+func NewRecorder(log *Log, cfg *config.Config, apiClient *datadog.APIClient) *Recorder {
+	return &Recorder{
+		log:       log,
+		cfg:       cfg,
+		operator:  operator(),
+		apiClient: apiClient,
+	}
+}
+
+// recorderKey is the context key of the Recorder
+type recorderKey struct{}
+
+// NewContext returns a context carrying the Recorder, used by the API clients
+// to record the changes they make
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext returns the Recorder carried by ctx, or nil
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Record records an action on target with the state before and after it, if
+// known, and its error, if it failed. Failures to record are logged; they do
+// not fail the action, which has already been made.
+func (r *Recorder) Record(ctx context.Context, action, target string, before, after interface{}, actionErr error) {
+	if r == nil {
+		return
+	}
+	
+	entry := &Entry{
+		Time:     time.Now().UTC(),
+		Operator: r.operator,
+		Profile:  r.cfg.Profile,
+		Site:     r.cfg.Site,
+		Action:   action,
+		Target:   target,
+		Before:   before,
+		After:    after,
+		Result:   ResultSuccess,
+	}
+	if actionErr != nil {
+		entry.Result = ResultFailed
+		entry.Error = actionErr.Error()
+	}
+	
+	if err := r.log.Append(entry); err != nil {
+		slog.Error("Failed to write audit log", "path", r.log.Path(), "action", action, "target", target, "error", err)
+	}
+	
+	if r.apiClient != nil {
+		if err := r.postEvent(ctx, entry); err != nil {
+			slog.Warn("Failed to post audit event", "action", action, "target", target, "error", err)
+		}
+	}
+}
+
+// postEvent posts an entry as a Datadog event
+func (r *Recorder) postEvent(ctx context.Context, entry *Entry) error {
+	eventsAPI := datadogV1.NewEventsApi(r.apiClient)
+	
+	title := fmt.Sprintf("%s %s by %s", entry.Action, entry.Target, entry.Operator)
+	details, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	text := string(details)
+	if len(text) > maxEventText {
+		text = text[:maxEventText]
+	}
+	
+	// Create the request body with proper initialization
+	body := *datadogV1.NewEventCreateRequest(text, title)
+	body.SetTags([]string{
+		"source:datadog-cli",
+		"audit_action:" + entry.Action,
+		"audit_operator:" + entry.Operator,
+		"audit_result:" + entry.Result,
+	})
+	body.SetSourceTypeName("datadog-cli")
+	if entry.Result == ResultFailed {
+		body.SetAlertType(datadogV1.EVENTALERTTYPE_ERROR)
+	}
+	
+	// Use proper error handling with context
+	_, httpResp, err := eventsAPI.CreateEvent(ctx, body)
+	if err != nil {
+		return ddapi.NewAPIError("posting audit event", httpResp, err)
+	}
+	
+	return nil
+}
+
+// operator returns the name of the user running the CLI
+func operator() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
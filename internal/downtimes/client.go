@@ -0,0 +1,281 @@
+package downtimes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/padawandba/datadog-cli/internal/audit"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
+)
+
+// Client provides downtime-related operations
+type Client struct {
+	apiClient *datadog.APIClient
+	ctx       context.Context
+}
+
+// NewClient creates a new downtimes client
+func NewClient(apiClient *datadog.APIClient, ctx context.Context) *Client {
+	return &Client{
+		apiClient: apiClient,
+		ctx:       ctx,
+	}
+}
+
+// Spec describes the downtime to create or the fields to change on update.
+// Empty fields are left unset.
+type Spec struct {
+	// MonitorID targets a single monitor
+	MonitorID int64
+	// MonitorTags targets every monitor carrying all of these tags
+	MonitorTags []string
+	// Scope is the query selecting the groups to silence (e.g., "env:prod")
+	Scope string
+	// Message is included with notifications about the downtime
+	Message string
+	// Timezone is used to display the downtime and to evaluate recurrences
+	Timezone string
+	// Start and End bound a one-off downtime
+	Start time.Time
+	End   time.Time
+	// RRule and Duration describe a recurring downtime
+	RRule    string
+	Duration string
+	// RecurrenceStart is the first occurrence of a recurring downtime, without a UTC offset
+	RecurrenceStart string
+}
+
+// List retrieves downtimes, optionally restricted to those currently active
+func (c *Client) List(currentOnly bool) ([]datadogV2.DowntimeResponseData, error) {
+	downtimesAPI := datadogV2.NewDowntimesApi(c.apiClient)
+	
+	// Create optional parameters with proper initialization
+	opts := datadogV2.NewListDowntimesOptionalParameters()
+	if currentOnly {
+		opts = opts.WithCurrentOnly(true)
+	}
+	
+	// Page through every downtime
+	results, cancel := downtimesAPI.ListDowntimesWithPagination(c.ctx, *opts)
+	defer cancel()
+	
+	downtimes := make([]datadogV2.DowntimeResponseData, 0)
+	for result := range results {
+		if result.Error != nil {
+			return nil, ddapi.NewAPIError("listing downtimes", nil, result.Error)
+		}
+		downtimes = append(downtimes, result.Item)
+	}
+	
+	return downtimes, nil
+}
+
+// Get retrieves a single downtime
+func (c *Client) Get(downtimeID string) (datadogV2.DowntimeResponseData, error) {
+	downtimesAPI := datadogV2.NewDowntimesApi(c.apiClient)
+	
+	// Use proper error handling with context
+	resp, httpResp, err := downtimesAPI.GetDowntime(c.ctx, downtimeID)
+	if err != nil {
+		return datadogV2.DowntimeResponseData{}, ddapi.NewAPIError("getting downtime", httpResp, err)
+	}
+	
+	return resp.GetData(), nil
+}
+
+// Create schedules a new downtime
+func (c *Client) Create(spec Spec) (datadogV2.DowntimeResponseData, error) {
+	downtimesAPI := datadogV2.NewDowntimesApi(c.apiClient)
+	
+	identifier, err := monitorIdentifier(spec)
+	if err != nil {
+		return datadogV2.DowntimeResponseData{}, err
+	}
+	if identifier == nil {
+		return datadogV2.DowntimeResponseData{}, fmt.Errorf("a monitor ID or monitor tags are required")
+	}
+	if spec.Scope == "" {
+		return datadogV2.DowntimeResponseData{}, fmt.Errorf("a scope is required")
+	}
+	
+	// Create the request body with proper initialization
+	attributes := *datadogV2.NewDowntimeCreateRequestAttributes(*identifier, spec.Scope)
+	if spec.Message != "" {
+		attributes.SetMessage(spec.Message)
+	}
+	if spec.Timezone != "" {
+		attributes.SetDisplayTimezone(spec.Timezone)
+	}
+	
+	if spec.RRule != "" {
+		recurrences, err := recurrencesSchedule(spec)
+		if err != nil {
+			return datadogV2.DowntimeResponseData{}, err
+		}
+		attributes.SetSchedule(datadogV2.DowntimeScheduleRecurrencesCreateRequestAsDowntimeScheduleCreateRequest(recurrences))
+	} else if oneTime := oneTimeSchedule(spec); oneTime != nil {
+		attributes.SetSchedule(datadogV2.DowntimeScheduleOneTimeCreateUpdateRequestAsDowntimeScheduleCreateRequest(oneTime))
+	}
+	
+	body := *datadogV2.NewDowntimeCreateRequest(
+		*datadogV2.NewDowntimeCreateRequestData(attributes, datadogV2.DOWNTIMERESOURCETYPE_DOWNTIME),
+	)
+	
+	// Use proper error handling with context
+	resp, httpResp, err := downtimesAPI.CreateDowntime(c.ctx, body)
+	if err != nil {
+		err = ddapi.NewAPIError("creating downtime", httpResp, err)
+		audit.FromContext(c.ctx).Record(c.ctx, "downtime.create", "scope:"+spec.Scope, nil, nil, err)
+		return datadogV2.DowntimeResponseData{}, err
+	}
+	
+	created := resp.GetData()
+	audit.FromContext(c.ctx).Record(c.ctx, "downtime.create", "downtime:"+created.GetId(), nil, simplifyDowntime(created), nil)
+	return created, nil
+}
+
+// Update changes the non-empty fields of spec on an existing downtime
+func (c *Client) Update(downtimeID string, spec Spec) (datadogV2.DowntimeResponseData, error) {
+	downtimesAPI := datadogV2.NewDowntimesApi(c.apiClient)
+	
+	identifier, err := monitorIdentifier(spec)
+	if err != nil {
+		return datadogV2.DowntimeResponseData{}, err
+	}
+	
+	// Create the request body with proper initialization
+	attributes := *datadogV2.NewDowntimeUpdateRequestAttributes()
+	if identifier != nil {
+		attributes.SetMonitorIdentifier(*identifier)
+	}
+	if spec.Scope != "" {
+		attributes.SetScope(spec.Scope)
+	}
+	if spec.Message != "" {
+		attributes.SetMessage(spec.Message)
+	}
+	if spec.Timezone != "" {
+		attributes.SetDisplayTimezone(spec.Timezone)
+	}
+	
+	if spec.RRule != "" {
+		recurrences, err := recurrencesSchedule(spec)
+		if err != nil {
+			return datadogV2.DowntimeResponseData{}, err
+		}
+		update := datadogV2.NewDowntimeScheduleRecurrencesUpdateRequest()
+		update.SetRecurrences(recurrences.GetRecurrences())
+		if recurrences.HasTimezone() {
+			update.SetTimezone(recurrences.GetTimezone())
+		}
+		attributes.SetSchedule(datadogV2.DowntimeScheduleRecurrencesUpdateRequestAsDowntimeScheduleUpdateRequest(update))
+	} else if oneTime := oneTimeSchedule(spec); oneTime != nil {
+		attributes.SetSchedule(datadogV2.DowntimeScheduleOneTimeCreateUpdateRequestAsDowntimeScheduleUpdateRequest(oneTime))
+	}
+	
+	body := *datadogV2.NewDowntimeUpdateRequest(
+		*datadogV2.NewDowntimeUpdateRequestData(attributes, downtimeID, datadogV2.DOWNTIMERESOURCETYPE_DOWNTIME),
+	)
+	
+	before := c.auditState(downtimeID)
+	
+	// Use proper error handling with context
+	resp, httpResp, err := downtimesAPI.UpdateDowntime(c.ctx, downtimeID, body)
+	if err != nil {
+		err = ddapi.NewAPIError("updating downtime", httpResp, err)
+		audit.FromContext(c.ctx).Record(c.ctx, "downtime.update", "downtime:"+downtimeID, before, nil, err)
+		return datadogV2.DowntimeResponseData{}, err
+	}
+	
+	updated := resp.GetData()
+	audit.FromContext(c.ctx).Record(c.ctx, "downtime.update", "downtime:"+downtimeID, before, simplifyDowntime(updated), nil)
+	return updated, nil
+}
+
+// Cancel cancels a downtime
+func (c *Client) Cancel(downtimeID string) error {
+	downtimesAPI := datadogV2.NewDowntimesApi(c.apiClient)
+	
+	before := c.auditState(downtimeID)
+	
+	// Use proper error handling with context
+	httpResp, err := downtimesAPI.CancelDowntime(c.ctx, downtimeID)
+	if err != nil {
+		err = ddapi.NewAPIError("canceling downtime", httpResp, err)
+	}
+	
+	audit.FromContext(c.ctx).Record(c.ctx, "downtime.cancel", "downtime:"+downtimeID, before, nil, err)
+	return err
+}
+
+// auditState returns a downtime as it is before a change, for the audit log,
+// or nil if it cannot be retrieved
+func (c *Client) auditState(downtimeID string) interface{} {
+	if audit.FromContext(c.ctx) == nil {
+		return nil
+	}
+	
+	downtime, err := c.Get(downtimeID)
+	if err != nil {
+		return nil
+	}
+	return simplifyDowntime(downtime)
+}
+
+// monitorIdentifier builds the monitor identifier for a spec, or nil if none was given
+func monitorIdentifier(spec Spec) (*datadogV2.DowntimeMonitorIdentifier, error) {
+	switch {
+	case spec.MonitorID != 0 && len(spec.MonitorTags) > 0:
+		return nil, fmt.Errorf("a monitor ID and monitor tags cannot be used together")
+	case spec.MonitorID != 0:
+		identifier := datadogV2.DowntimeMonitorIdentifierIdAsDowntimeMonitorIdentifier(
+			datadogV2.NewDowntimeMonitorIdentifierId(spec.MonitorID))
+		return &identifier, nil
+	case len(spec.MonitorTags) > 0:
+		identifier := datadogV2.DowntimeMonitorIdentifierTagsAsDowntimeMonitorIdentifier(
+			datadogV2.NewDowntimeMonitorIdentifierTags(spec.MonitorTags))
+		return &identifier, nil
+	default:
+		return nil, nil
+	}
+}
+
+// oneTimeSchedule builds a one-off schedule for a spec, or nil if no bounds were given
+func oneTimeSchedule(spec Spec) *datadogV2.DowntimeScheduleOneTimeCreateUpdateRequest {
+	if spec.Start.IsZero() && spec.End.IsZero() {
+		return nil
+	}
+	
+	schedule := datadogV2.NewDowntimeScheduleOneTimeCreateUpdateRequest()
+	if !spec.Start.IsZero() {
+		schedule.SetStart(spec.Start)
+	}
+	if !spec.End.IsZero() {
+		schedule.SetEnd(spec.End)
+	}
+	return schedule
+}
+
+// recurrencesSchedule builds a recurring schedule for a spec
+func recurrencesSchedule(spec Spec) (*datadogV2.DowntimeScheduleRecurrencesCreateRequest, error) {
+	if spec.Duration == "" {
+		return nil, fmt.Errorf("a recurrence duration is required with an RRULE")
+	}
+	
+	rrule := strings.TrimPrefix(spec.RRule, "RRULE:")
+	recurrence := *datadogV2.NewDowntimeScheduleRecurrenceCreateUpdateRequest(spec.Duration, rrule)
+	if spec.RecurrenceStart != "" {
+		recurrence.SetStart(spec.RecurrenceStart)
+	}
+	
+	schedule := datadogV2.NewDowntimeScheduleRecurrencesCreateRequest(
+		[]datadogV2.DowntimeScheduleRecurrenceCreateUpdateRequest{recurrence})
+	if spec.Timezone != "" {
+		schedule.SetTimezone(spec.Timezone)
+	}
+	return schedule, nil
+}
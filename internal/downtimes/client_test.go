@@ -0,0 +1,108 @@
+package downtimes
+
+import (
+	"testing"
+)
+
+func TestMonitorIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     Spec
+		wantID   int64
+		wantTags []string
+		wantNil  bool
+		wantErr  bool
+	}{
+		{name: "id", spec: Spec{MonitorID: 42}, wantID: 42},
+		{name: "tags", spec: Spec{MonitorTags: []string{"team:sre", "env:prod"}}, wantTags: []string{"team:sre", "env:prod"}},
+		{name: "neither", spec: Spec{Scope: "env:prod"}, wantNil: true},
+		{name: "both", spec: Spec{MonitorID: 42, MonitorTags: []string{"team:sre"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := monitorIdentifier(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("monitorIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			switch {
+			case tt.wantErr:
+			case tt.wantNil:
+				if got != nil {
+					t.Errorf("monitorIdentifier() = %+v, want nil", got)
+				}
+			case tt.wantID != 0:
+				if got == nil || got.DowntimeMonitorIdentifierId == nil || got.DowntimeMonitorIdentifierId.GetMonitorId() != tt.wantID {
+					t.Errorf("monitorIdentifier() = %+v, want monitor ID %d", got, tt.wantID)
+				}
+			default:
+				if got == nil || got.DowntimeMonitorIdentifierTags == nil {
+					t.Fatalf("monitorIdentifier() = %+v, want monitor tags", got)
+				}
+				tags := got.DowntimeMonitorIdentifierTags.GetMonitorTags()
+				if len(tags) != len(tt.wantTags) || tags[0] != tt.wantTags[0] || tags[1] != tt.wantTags[1] {
+					t.Errorf("monitor tags = %v, want %v", tags, tt.wantTags)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrencesSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      Spec
+		wantRRule string
+		wantStart string
+		wantZone  string
+		wantErr   bool
+	}{
+		{
+			name:      "strips the RRULE prefix",
+			spec:      Spec{RRule: "RRULE:FREQ=WEEKLY;BYDAY=SA", Duration: "1d", Timezone: "Europe/Paris"},
+			wantRRule: "FREQ=WEEKLY;BYDAY=SA",
+			wantZone:  "Europe/Paris",
+		},
+		{
+			name:      "keeps the start as-is",
+			spec:      Spec{RRule: "FREQ=DAILY", Duration: "2h", RecurrenceStart: "2024-05-01T22:00"},
+			wantRRule: "FREQ=DAILY",
+			wantStart: "2024-05-01T22:00",
+			wantZone:  "UTC",
+		},
+		{
+			name:    "requires a duration",
+			spec:    Spec{RRule: "FREQ=DAILY"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := recurrencesSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recurrencesSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			recurrences := got.GetRecurrences()
+			if len(recurrences) != 1 {
+				t.Fatalf("recurrencesSchedule() has %d recurrences, want 1", len(recurrences))
+			}
+			recurrence := recurrences[0]
+			if recurrence.GetRrule() != tt.wantRRule {
+				t.Errorf("rrule = %q, want %q", recurrence.GetRrule(), tt.wantRRule)
+			}
+			if recurrence.GetDuration() != tt.spec.Duration {
+				t.Errorf("duration = %q, want %q", recurrence.GetDuration(), tt.spec.Duration)
+			}
+			if recurrence.GetStart() != tt.wantStart {
+				t.Errorf("start = %q, want %q", recurrence.GetStart(), tt.wantStart)
+			}
+			if got.GetTimezone() != tt.wantZone {
+				t.Errorf("timezone = %q, want %q", got.GetTimezone(), tt.wantZone)
+			}
+		})
+	}
+}
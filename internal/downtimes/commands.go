@@ -0,0 +1,316 @@
+package downtimes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommands returns the downtimes command group
+func NewCommands(apiClient *datadog.APIClient, ctx context.Context, cfg *config.Config) *cli.Command {
+	client := NewClient(apiClient, ctx)
+	
+	return &cli.Command{
+		Name:  "downtimes",
+		Usage: "Manage Datadog downtimes",
+		Subcommands: []*cli.Command{
+			listCommand(client, cfg),
+			getCommand(client, cfg),
+			createCommand(client, cfg),
+			updateCommand(client, cfg),
+			cancelCommand(client),
+		},
+	}
+}
+
+// listCommand returns the command to list downtimes
+func listCommand(client *Client, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List active and scheduled downtimes",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "active",
+				Usage: "Only show downtimes that are currently active",
+			},
+			&cli.BoolFlag{
+				Name:  "scheduled",
+				Usage: "Only show downtimes that have not started yet",
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Include ended and canceled downtimes",
+			},
+			&cli.StringFlag{
+				Name:  "scope",
+				Usage: "Only show downtimes whose scope contains this text (e.g., 'env:prod')",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("active") && c.Bool("scheduled") {
+				return fmt.Errorf("--active and --scheduled cannot be used together")
+			}
+			
+			downtimes, err := client.List(c.Bool("active"))
+			if err != nil {
+				return fmt.Errorf("failed to list downtimes: %w", err)
+			}
+			
+			filtered := filterDowntimes(downtimes, listFilter{
+				Active:    c.Bool("active"),
+				Scheduled: c.Bool("scheduled"),
+				All:       c.Bool("all"),
+				Scope:     c.String("scope"),
+			})
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			return FormatDowntimes(formatter, filtered)
+		},
+	}
+}
+
+// listFilter selects the downtimes shown by the list command
+type listFilter struct {
+	// Active and Scheduled keep only downtimes in that status
+	Active    bool
+	Scheduled bool
+	// All keeps ended and canceled downtimes
+	All bool
+	// Scope keeps downtimes whose scope contains it
+	Scope string
+}
+
+// filterDowntimes returns the downtimes matching filter, in order
+func filterDowntimes(downtimes []datadogV2.DowntimeResponseData, filter listFilter) []datadogV2.DowntimeResponseData {
+	filtered := make([]datadogV2.DowntimeResponseData, 0, len(downtimes))
+	for _, downtime := range downtimes {
+		attributes := downtime.GetAttributes()
+		status := attributes.GetStatus()
+		
+		switch {
+		case filter.Active && status != datadogV2.DOWNTIMESTATUS_ACTIVE:
+			continue
+		case filter.Scheduled && status != datadogV2.DOWNTIMESTATUS_SCHEDULED:
+			continue
+		case !filter.All && (status == datadogV2.DOWNTIMESTATUS_ENDED || status == datadogV2.DOWNTIMESTATUS_CANCELED):
+			continue
+		case filter.Scope != "" && !strings.Contains(attributes.GetScope(), filter.Scope):
+			continue
+		}
+		filtered = append(filtered, downtime)
+	}
+	return filtered
+}
+
+// getCommand returns the command to show a single downtime
+func getCommand(client *Client, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "get",
+		Usage:     "Show a downtime",
+		ArgsUsage: "DOWNTIME_ID",
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("downtime ID argument is required")
+			}
+			
+			downtime, err := client.Get(c.Args().First())
+			if err != nil {
+				return fmt.Errorf("failed to get downtime: %w", err)
+			}
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			return FormatDowntime(formatter, downtime)
+		},
+	}
+}
+
+// specFlags returns the flags describing a downtime for create and update
+func specFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "monitor-id",
+			Usage: "Monitor to silence",
+		},
+		&cli.StringSliceFlag{
+			Name:  "monitor-tags",
+			Usage: "Silence every monitor carrying all of these tags",
+		},
+		&cli.StringFlag{
+			Name:  "scope",
+			Usage: "Scope to silence (e.g., 'env:prod AND service:checkout')",
+		},
+		&cli.StringFlag{
+			Name:    "message",
+			Aliases: []string{"m"},
+			Usage:   "Message included with downtime notifications",
+		},
+		&cli.StringFlag{
+			Name:  "timezone",
+			Usage: "Timezone used to display the downtime and evaluate recurrences (e.g., 'Europe/Paris')",
+		},
+		&cli.StringFlag{
+			Name:  "start",
+			Usage: "Start time (RFC3339 for one-off downtimes, e.g. '2024-05-01T22:00:00Z'; without offset for recurring, e.g. '2024-05-01T22:00')",
+		},
+		&cli.StringFlag{
+			Name:  "end",
+			Usage: "End time of a one-off downtime (RFC3339)",
+		},
+		&cli.StringFlag{
+			Name:    "duration",
+			Aliases: []string{"d"},
+			Usage:   "Length of the downtime (e.g., 2h for one-off downtimes; 2h, 1d or 1w for each recurrence)",
+		},
+		&cli.StringFlag{
+			Name:  "rrule",
+			Usage: "Recurrence rule for a recurring downtime (e.g., 'FREQ=WEEKLY;BYDAY=SA')",
+		},
+	}
+}
+
+// specFromContext builds a downtime Spec from the specFlags
+func specFromContext(c *cli.Context) (Spec, error) {
+	spec := Spec{
+		MonitorID:   c.Int64("monitor-id"),
+		MonitorTags: c.StringSlice("monitor-tags"),
+		Scope:       c.String("scope"),
+		Message:     c.String("message"),
+		Timezone:    c.String("timezone"),
+		RRule:       c.String("rrule"),
+	}
+	
+	// Recurring downtimes take the duration and start as-is
+	if spec.RRule != "" {
+		if c.String("end") != "" {
+			return spec, fmt.Errorf("--end cannot be used with --rrule; use --duration for the length of each recurrence")
+		}
+		spec.Duration = c.String("duration")
+		spec.RecurrenceStart = c.String("start")
+		return spec, nil
+	}
+	
+	if start := c.String("start"); start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return spec, fmt.Errorf("invalid start time: %v", err)
+		}
+		spec.Start = t
+	}
+	
+	if end := c.String("end"); end != "" {
+		if c.String("duration") != "" {
+			return spec, fmt.Errorf("--end and --duration cannot be used together")
+		}
+		t, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return spec, fmt.Errorf("invalid end time: %v", err)
+		}
+		spec.End = t
+	} else if durationStr := c.String("duration"); durationStr != "" {
+		duration, err := time.ParseDuration(durationStr)
+		if err != nil {
+			return spec, fmt.Errorf("invalid duration format: %v", err)
+		}
+		start := spec.Start
+		if start.IsZero() {
+			start = time.Now()
+		}
+		spec.End = start.Add(duration)
+	}
+	
+	return spec, nil
+}
+
+// createCommand returns the command to schedule a downtime
+func createCommand(client *Client, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "create",
+		Usage: "Schedule a one-off or recurring downtime",
+		Flags: specFlags(),
+		Action: func(c *cli.Context) error {
+			spec, err := specFromContext(c)
+			if err != nil {
+				return err
+			}
+			
+			downtime, err := client.Create(spec)
+			if err != nil {
+				return fmt.Errorf("failed to create downtime: %w", err)
+			}
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			return FormatDowntime(formatter, downtime)
+		},
+	}
+}
+
+// updateCommand returns the command to change a downtime
+func updateCommand(client *Client, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "update",
+		Usage:     "Update a downtime; only the given flags are changed",
+		ArgsUsage: "DOWNTIME_ID",
+		Flags:     specFlags(),
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("downtime ID argument is required")
+			}
+			
+			spec, err := specFromContext(c)
+			if err != nil {
+				return err
+			}
+			
+			downtime, err := client.Update(c.Args().First(), spec)
+			if err != nil {
+				return fmt.Errorf("failed to update downtime: %w", err)
+			}
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			return FormatDowntime(formatter, downtime)
+		},
+	}
+}
+
+// cancelCommand returns the command to cancel a downtime
+func cancelCommand(client *Client) *cli.Command {
+	return &cli.Command{
+		Name:      "cancel",
+		Usage:     "Cancel a downtime",
+		ArgsUsage: "DOWNTIME_ID",
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("downtime ID argument is required")
+			}
+			
+			downtimeID := c.Args().First()
+			
+			fmt.Printf("Canceling downtime %s\n", downtimeID)
+			return client.Cancel(downtimeID)
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package downtimes
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/urfave/cli/v2"
+)
+
+// specContext parses args with the specFlags into a cli.Context
+func specContext(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range specFlags() {
+		if err := f.Apply(set); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+func TestSpecFromContext(t *testing.T) {
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    Spec
+		wantErr bool
+	}{
+		{
+			name: "one-off with end",
+			args: []string{"--monitor-id", "42", "--start", "2024-05-01T22:00:00Z", "--end", "2024-05-02T02:00:00Z"},
+			want: Spec{MonitorID: 42, Start: start, End: start.Add(4 * time.Hour)},
+		},
+		{
+			name: "one-off with duration",
+			args: []string{"--scope", "env:prod", "--start", "2024-05-01T22:00:00Z", "--duration", "2h"},
+			want: Spec{Scope: "env:prod", Start: start, End: start.Add(2 * time.Hour)},
+		},
+		{
+			name: "recurring takes start and duration as-is",
+			args: []string{"--monitor-tags", "team:sre", "--rrule", "FREQ=WEEKLY;BYDAY=SA", "--start", "2024-05-01T22:00", "--duration", "1d"},
+			want: Spec{MonitorTags: []string{"team:sre"}, RRule: "FREQ=WEEKLY;BYDAY=SA", RecurrenceStart: "2024-05-01T22:00", Duration: "1d"},
+		},
+		{
+			name:    "end with rrule",
+			args:    []string{"--rrule", "FREQ=DAILY", "--end", "2024-05-02T02:00:00Z"},
+			wantErr: true,
+		},
+		{
+			name:    "end with duration",
+			args:    []string{"--end", "2024-05-02T02:00:00Z", "--duration", "2h"},
+			wantErr: true,
+		},
+		{
+			name:    "one-off start without offset",
+			args:    []string{"--start", "2024-05-01T22:00"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			args:    []string{"--duration", "1d"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := specFromContext(specContext(t, tt.args...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("specFromContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.MonitorID != tt.want.MonitorID || got.Scope != tt.want.Scope ||
+				got.RRule != tt.want.RRule || got.Duration != tt.want.Duration ||
+				got.RecurrenceStart != tt.want.RecurrenceStart ||
+				!got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) ||
+				len(got.MonitorTags) != len(tt.want.MonitorTags) {
+				t.Errorf("specFromContext() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testDowntime builds a downtime response with a status and scope
+func testDowntime(id string, status datadogV2.DowntimeStatus, scope string) datadogV2.DowntimeResponseData {
+	attributes := datadogV2.NewDowntimeResponseAttributes()
+	attributes.SetStatus(status)
+	attributes.SetScope(scope)
+	downtime := datadogV2.NewDowntimeResponseData()
+	downtime.SetId(id)
+	downtime.SetAttributes(*attributes)
+	return *downtime
+}
+
+func TestFilterDowntimes(t *testing.T) {
+	downtimes := []datadogV2.DowntimeResponseData{
+		testDowntime("active", datadogV2.DOWNTIMESTATUS_ACTIVE, "env:prod"),
+		testDowntime("scheduled", datadogV2.DOWNTIMESTATUS_SCHEDULED, "env:staging"),
+		testDowntime("ended", datadogV2.DOWNTIMESTATUS_ENDED, "env:prod"),
+		testDowntime("canceled", datadogV2.DOWNTIMESTATUS_CANCELED, "env:prod AND service:web"),
+	}
+
+	tests := []struct {
+		name   string
+		filter listFilter
+		want   []string
+	}{
+		{name: "default hides ended and canceled", filter: listFilter{}, want: []string{"active", "scheduled"}},
+		{name: "active", filter: listFilter{Active: true}, want: []string{"active"}},
+		{name: "scheduled", filter: listFilter{Scheduled: true}, want: []string{"scheduled"}},
+		{name: "all", filter: listFilter{All: true}, want: []string{"active", "scheduled", "ended", "canceled"}},
+		{name: "scope", filter: listFilter{All: true, Scope: "env:prod"}, want: []string{"active", "ended", "canceled"}},
+		{name: "scope without all", filter: listFilter{Scope: "service:web"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterDowntimes(downtimes, tt.filter)
+			ids := make([]string, len(got))
+			for i, downtime := range got {
+				ids[i] = downtime.GetId()
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("filterDowntimes() = %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Errorf("filterDowntimes() = %v, want %v", ids, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
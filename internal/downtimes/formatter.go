@@ -0,0 +1,108 @@
+package downtimes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+)
+
+// FormatDowntimes formats a slice of Datadog downtimes for display
+func FormatDowntimes(formatter *console.Formatter, downtimes []datadogV2.DowntimeResponseData) error {
+	// Convert the downtimes to a simplified format for display
+	simplifiedDowntimes := make([]SimplifiedDowntime, 0, len(downtimes))
+	for _, downtime := range downtimes {
+		simplifiedDowntimes = append(simplifiedDowntimes, simplifyDowntime(downtime))
+	}
+
+	// Use the formatter to display the simplified downtimes
+	return formatter.Format(simplifiedDowntimes)
+}
+
+// FormatDowntime formats a single Datadog downtime for display
+func FormatDowntime(formatter *console.Formatter, downtime datadogV2.DowntimeResponseData) error {
+	return formatter.Format(simplifyDowntime(downtime))
+}
+
+// SimplifiedDowntime is a simplified representation of a Datadog downtime
+type SimplifiedDowntime struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Scope    string `json:"scope"`
+	Monitor  string `json:"monitor"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Schedule string `json:"schedule"`
+	Timezone string `json:"timezone"`
+	Message  string `json:"message"`
+}
+
+// DefaultColumns returns the columns shown in table output unless -o wide is used
+func (SimplifiedDowntime) DefaultColumns() []string {
+	return []string{"id", "status", "scope", "monitor", "start", "end"}
+}
+
+// Kind names downtimes in JSON envelopes and schemas
+func (SimplifiedDowntime) Kind() string {
+	return "Downtime"
+}
+
+// simplifyDowntime converts a Datadog downtime to a SimplifiedDowntime
+func simplifyDowntime(downtime datadogV2.DowntimeResponseData) SimplifiedDowntime {
+	simplified := SimplifiedDowntime{
+		ID: downtime.GetId(),
+	}
+	
+	attributes := downtime.GetAttributes()
+	simplified.Status = string(attributes.GetStatus())
+	simplified.Scope = attributes.GetScope()
+	simplified.Message = attributes.GetMessage()
+	simplified.Timezone = attributes.GetDisplayTimezone()
+	
+	// Handle the monitor identifier
+	if attributes.HasMonitorIdentifier() {
+		identifier := attributes.GetMonitorIdentifier()
+		if identifier.DowntimeMonitorIdentifierId != nil {
+			simplified.Monitor = fmt.Sprintf("id:%d", identifier.DowntimeMonitorIdentifierId.GetMonitorId())
+		} else if identifier.DowntimeMonitorIdentifierTags != nil {
+			simplified.Monitor = "tags:" + strings.Join(identifier.DowntimeMonitorIdentifierTags.GetMonitorTags(), ",")
+		}
+	}
+	
+	// Handle the schedule
+	if attributes.HasSchedule() {
+		schedule := attributes.GetSchedule()
+		if oneTime := schedule.DowntimeScheduleOneTimeResponse; oneTime != nil {
+			simplified.Schedule = "once"
+			simplified.Start = oneTime.GetStart().Format(time.RFC3339)
+			if end, ok := oneTime.GetEndOk(); ok && end != nil {
+				simplified.End = end.Format(time.RFC3339)
+			}
+		} else if recurring := schedule.DowntimeScheduleRecurrencesResponse; recurring != nil {
+			rules := make([]string, 0, len(recurring.GetRecurrences()))
+			for _, recurrence := range recurring.GetRecurrences() {
+				rules = append(rules, fmt.Sprintf("%s for %s", recurrence.GetRrule(), recurrence.GetDuration()))
+			}
+			simplified.Schedule = strings.Join(rules, "; ")
+			
+			// Show the current or next occurrence
+			if recurring.HasCurrentDowntime() {
+				current := recurring.GetCurrentDowntime()
+				if current.HasStart() {
+					simplified.Start = current.GetStart().Format(time.RFC3339)
+				}
+				if end, ok := current.GetEndOk(); ok && end != nil {
+					simplified.End = end.Format(time.RFC3339)
+				}
+			}
+		}
+	}
+	
+	if simplified.End == "" && simplified.Start != "" {
+		simplified.End = "indefinitely"
+	}
+	
+	return simplified
+}
@@ -0,0 +1,72 @@
+package downtimes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+)
+
+func TestSimplifyDowntime(t *testing.T) {
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+
+	// A one-off downtime on a single monitor
+	oneTime := datadogV2.NewDowntimeScheduleOneTimeResponse(start)
+	oneTime.SetEnd(start.Add(2 * time.Hour))
+	once := testDowntime("once", datadogV2.DOWNTIMESTATUS_SCHEDULED, "env:prod")
+	once.Attributes.SetSchedule(datadogV2.DowntimeScheduleOneTimeResponseAsDowntimeScheduleResponse(oneTime))
+	once.Attributes.SetMonitorIdentifier(datadogV2.DowntimeMonitorIdentifierIdAsDowntimeMonitorIdentifier(
+		datadogV2.NewDowntimeMonitorIdentifierId(42)))
+
+	// A one-off downtime without an end
+	open := testDowntime("open", datadogV2.DOWNTIMESTATUS_ACTIVE, "*")
+	open.Attributes.SetSchedule(datadogV2.DowntimeScheduleOneTimeResponseAsDowntimeScheduleResponse(
+		datadogV2.NewDowntimeScheduleOneTimeResponse(start)))
+
+	// A recurring downtime on tagged monitors, currently in an occurrence
+	recurrence := datadogV2.NewDowntimeScheduleRecurrenceResponse()
+	recurrence.SetRrule("FREQ=WEEKLY;BYDAY=SA")
+	recurrence.SetDuration("1d")
+	current := datadogV2.NewDowntimeScheduleCurrentDowntimeResponse()
+	current.SetStart(start)
+	current.SetEnd(start.Add(24 * time.Hour))
+	recurrences := datadogV2.NewDowntimeScheduleRecurrencesResponse([]datadogV2.DowntimeScheduleRecurrenceResponse{*recurrence})
+	recurrences.SetCurrentDowntime(*current)
+	weekly := testDowntime("weekly", datadogV2.DOWNTIMESTATUS_ACTIVE, "env:prod")
+	weekly.Attributes.SetSchedule(datadogV2.DowntimeScheduleRecurrencesResponseAsDowntimeScheduleResponse(recurrences))
+	weekly.Attributes.SetMonitorIdentifier(datadogV2.DowntimeMonitorIdentifierTagsAsDowntimeMonitorIdentifier(
+		datadogV2.NewDowntimeMonitorIdentifierTags([]string{"team:sre", "env:prod"})))
+
+	tests := []struct {
+		name     string
+		downtime datadogV2.DowntimeResponseData
+		want     SimplifiedDowntime
+	}{
+		{
+			name:     "one-off",
+			downtime: once,
+			want: SimplifiedDowntime{ID: "once", Status: "scheduled", Scope: "env:prod", Monitor: "id:42",
+				Start: "2024-05-01T22:00:00Z", End: "2024-05-02T00:00:00Z", Schedule: "once", Timezone: "UTC"},
+		},
+		{
+			name:     "one-off without end",
+			downtime: open,
+			want: SimplifiedDowntime{ID: "open", Status: "active", Scope: "*",
+				Start: "2024-05-01T22:00:00Z", End: "indefinitely", Schedule: "once", Timezone: "UTC"},
+		},
+		{
+			name:     "recurring",
+			downtime: weekly,
+			want: SimplifiedDowntime{ID: "weekly", Status: "active", Scope: "env:prod", Monitor: "tags:team:sre,env:prod",
+				Start: "2024-05-01T22:00:00Z", End: "2024-05-02T22:00:00Z", Schedule: "FREQ=WEEKLY;BYDAY=SA for 1d", Timezone: "UTC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := simplifyDowntime(tt.downtime); got != tt.want {
+				t.Errorf("simplifyDowntime() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,12 @@ package hosts
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/padawandba/datadog-cli/internal/audit"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
 )
 
 // Client provides host-related operations
@@ -36,11 +37,7 @@ func (c *Client) List(filter string) ([]datadogV1.Host, error) {
 	// Use proper error handling with context
 	resp, httpResp, err := hostsAPI.ListHosts(c.ctx, *opts)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return nil, fmt.Errorf("error listing hosts (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return nil, fmt.Errorf("error listing hosts: %v", err)
+		return nil, ddapi.NewAPIError("listing hosts", httpResp, err)
 	}
 	
 	return resp.GetHostList(), nil
@@ -66,13 +63,12 @@ func (c *Client) Mute(hostname string, message string, end time.Time) error {
 	// Use proper error handling with context
 	_, httpResp, err := hostsAPI.MuteHost(c.ctx, hostname, body)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error muting host (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return fmt.Errorf("error muting host: %v", err)
+		err = ddapi.NewAPIError("muting host", httpResp, err)
+		audit.FromContext(c.ctx).Record(c.ctx, "host.mute", "host:"+hostname, nil, nil, err)
+		return err
 	}
 	
+	audit.FromContext(c.ctx).Record(c.ctx, "host.mute", "host:"+hostname, nil, body, nil)
 	return nil
 }
 
@@ -83,12 +79,9 @@ func (c *Client) Unmute(hostname string) error {
 	// Use proper error handling with context
 	_, httpResp, err := hostsAPI.UnmuteHost(c.ctx, hostname)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error unmuting host (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return fmt.Errorf("error unmuting host: %v", err)
+		err = ddapi.NewAPIError("unmuting host", httpResp, err)
 	}
 	
-	return nil
+	audit.FromContext(c.ctx).Record(c.ctx, "host.unmute", "host:"+hostname, nil, nil, err)
+	return err
 }
@@ -40,17 +40,23 @@ func listCommand(client *Client, cfg *config.Config) *cli.Command {
 		Action: func(c *cli.Context) error {
 			filter := c.String("filter")
 			
-			hosts, err := client.List(filter)
+			formatter, err := console.NewFormatterFromConfig(cfg)
 			if err != nil {
-				return fmt.Errorf("failed to list hosts: %v", err)
+				return err
 			}
 			
-			formatter := console.NewFormatter(cfg.Output)
-			if c.String("output") != "" {
-				formatter = console.NewFormatter(c.String("output"))
+			render := func() error {
+				hosts, err := client.List(filter)
+				if err != nil {
+					return fmt.Errorf("failed to list hosts: %w", err)
+				}
+				return FormatHosts(formatter, hosts)
 			}
 			
-			return FormatHosts(formatter, hosts)
+			if cfg.Watch > 0 {
+				return console.Watch(c.Context, formatter, cfg.Watch, "hosts list", render)
+			}
+			return render()
 		},
 	}
 }
@@ -11,14 +11,17 @@ import (
 
 // FormatHosts formats a slice of Host structs for display
 func FormatHosts(formatter *console.Formatter, hosts []datadogV1.Host) error {
-	// Convert the hosts to a simplified format for display
+	// Use the formatter to display the simplified hosts
+	return formatter.Format(SimplifyHosts(hosts))
+}
+
+// SimplifyHosts converts Datadog hosts to the simplified format used for display
+func SimplifyHosts(hosts []datadogV1.Host) []SimplifiedHost {
 	simplifiedHosts := make([]SimplifiedHost, 0, len(hosts))
 	for _, host := range hosts {
 		simplifiedHosts = append(simplifiedHosts, simplifyHost(host))
 	}
-
-	// Use the formatter to display the simplified hosts
-	return formatter.Format(simplifiedHosts)
+	return simplifiedHosts
 }
 
 // SimplifiedHost is a simplified representation of a Datadog Host
@@ -34,6 +37,16 @@ type SimplifiedHost struct {
 	TagsBySource    string   `json:"tags_by_source"`
 }
 
+// DefaultColumns returns the columns shown in table output unless -o wide is used
+func (SimplifiedHost) DefaultColumns() []string {
+	return []string{"name", "up", "is_muted", "last_reported_at", "apps"}
+}
+
+// Kind names hosts in JSON envelopes and schemas
+func (SimplifiedHost) Kind() string {
+	return "Host"
+}
+
 // simplifyHost converts a Datadog Host to a SimplifiedHost
 func simplifyHost(host datadogV1.Host) SimplifiedHost {
 	simplified := SimplifiedHost{}
@@ -3,10 +3,15 @@ package monitors
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/padawandba/datadog-cli/internal/audit"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
 )
 
 // Client provides monitor-related operations
@@ -35,6 +40,16 @@ type Monitor struct {
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
+// DefaultColumns returns the columns shown in table output unless -o wide is used
+func (Monitor) DefaultColumns() []string {
+	return []string{"id", "name", "status", "type", "tags"}
+}
+
+// Kind names monitors in JSON envelopes and schemas
+func (Monitor) Kind() string {
+	return "Monitor"
+}
+
 // List retrieves a list of monitors
 func (c *Client) List(query string, tags []string) ([]Monitor, error) {
 	monitorsAPI := datadogV1.NewMonitorsApi(c.apiClient)
@@ -51,11 +66,7 @@ func (c *Client) List(query string, tags []string) ([]Monitor, error) {
 	// Use proper error handling with context
 	resp, httpResp, err := monitorsAPI.ListMonitors(c.ctx, *opts)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return nil, fmt.Errorf("error listing monitors (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return nil, fmt.Errorf("error listing monitors: %v", err)
+		return nil, ddapi.NewAPIError("listing monitors", httpResp, err)
 	}
 	
 	// Convert API response to our simplified Monitor type
@@ -76,50 +87,354 @@ func (c *Client) List(query string, tags []string) ([]Monitor, error) {
 	return monitors, nil
 }
 
-// Mute mutes a monitor
-func (c *Client) Mute(monitorID int64, scope string, endTime int64) error {
+// StateCount is the number of monitors in a single overall state
+type StateCount struct {
+	State string `json:"state"`
+	Count int    `json:"count"`
+}
+
+// TriggeredGroup is a monitor group that is currently alerting, warning or reporting no data
+type TriggeredGroup struct {
+	MonitorID   int64  `json:"monitor_id"`
+	Monitor     string `json:"monitor"`
+	Group       string `json:"group"`
+	Status      string `json:"status"`
+	TriggeredAt string `json:"triggered_at"`
+	InState     string `json:"in_state"`
+}
+
+// StatusSummary is a triage view of monitor states
+type StatusSummary struct {
+	Counts    []StateCount     `json:"counts"`
+	Triggered []TriggeredGroup `json:"triggered"`
+}
+
+// Kind names status summaries in JSON envelopes and schemas
+func (StatusSummary) Kind() string {
+	return "MonitorStatus"
+}
+
+// summaryStates lists the overall states reported by Status, in display order
+var summaryStates = []datadogV1.MonitorOverallStates{
+	datadogV1.MONITOROVERALLSTATES_ALERT,
+	datadogV1.MONITOROVERALLSTATES_WARN,
+	datadogV1.MONITOROVERALLSTATES_NO_DATA,
+	datadogV1.MONITOROVERALLSTATES_OK,
+	datadogV1.MONITOROVERALLSTATES_SKIPPED,
+	datadogV1.MONITOROVERALLSTATES_UNKNOWN,
+}
+
+// Status summarizes monitor states and lists the groups that are currently triggered
+func (c *Client) Status(tags []string) (*StatusSummary, error) {
 	monitorsAPI := datadogV1.NewMonitorsApi(c.apiClient)
 	
-	// Get the current monitor
-	monitor, httpResp, err := monitorsAPI.GetMonitor(c.ctx, monitorID)
+	// Request the state of every group that is not OK
+	opts := datadogV1.NewListMonitorsOptionalParameters().WithGroupStates("alert,warn,no data")
+	if len(tags) > 0 {
+		opts = opts.WithTags(strings.Join(tags, ","))
+	}
+	
+	// Use proper error handling with context
+	resp, httpResp, err := monitorsAPI.ListMonitors(c.ctx, *opts)
+	if err != nil {
+		return nil, ddapi.NewAPIError("listing monitors", httpResp, err)
+	}
+	
+	return summarizeStatus(resp, time.Now()), nil
+}
+
+// SearchResult holds one page of monitor search results along with facet counts
+type SearchResult struct {
+	Monitors   []Monitor
+	Facets     []FacetCount
+	Page       int64
+	PageCount  int64
+	PerPage    int64
+	TotalCount int64
+}
+
+// FacetCount is the number of matching monitors for a single facet value
+type FacetCount struct {
+	Facet string `json:"facet"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Kind names facet counts in JSON envelopes and schemas
+func (FacetCount) Kind() string {
+	return "MonitorFacet"
+}
+
+// Search retrieves a single page of monitors matching a monitor search query
+// (e.g., "status:alert type:metric tag:team:sre priority:1"). The creator
+// facet counts only the returned page, unlike the other facets, which cover
+// every matching monitor; SearchAll sums it over every page.
+func (c *Client) Search(query string, page int64, perPage int64, sortBy string) (*SearchResult, error) {
+	monitorsAPI := datadogV1.NewMonitorsApi(c.apiClient)
+	
+	// Create optional parameters with proper initialization
+	opts := datadogV1.NewSearchMonitorsOptionalParameters().WithPage(page)
+	if query != "" {
+		opts = opts.WithQuery(query)
+	}
+	if perPage > 0 {
+		opts = opts.WithPerPage(perPage)
+	}
+	if sortBy != "" {
+		opts = opts.WithSort(sortBy)
+	}
+	
+	// Use proper error handling with context
+	resp, httpResp, err := monitorsAPI.SearchMonitors(c.ctx, *opts)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error getting monitor (status: %d): %v", httpResp.StatusCode, err)
+		return nil, ddapi.NewAPIError("searching monitors", httpResp, err)
+	}
+	
+	result := &SearchResult{
+		Monitors: make([]Monitor, 0, len(resp.GetMonitors())),
+	}
+	
+	// Convert search results to our simplified Monitor type
+	creators := make(map[string]int64)
+	for _, m := range resp.GetMonitors() {
+		result.Monitors = append(result.Monitors, Monitor{
+			ID:     m.GetId(),
+			Name:   m.GetName(),
+			Status: string(m.GetStatus()),
+			Type:   string(m.GetType()),
+			Query:  m.GetQuery(),
+			Tags:   m.GetTags(),
+		})
+		
+		// The search API has no creator facet, so count creators from the page itself
+		if m.HasCreator() {
+			creator := m.GetCreator()
+			creators[creator.GetHandle()]++
 		}
-		return fmt.Errorf("error getting monitor: %v", err)
 	}
 	
-	// Create an update request with proper initialization
-	updateReq := *datadogV1.NewMonitorUpdateRequest()
+	// Collect facet counts returned by the API
+	counts := resp.GetCounts()
+	result.Facets = append(result.Facets, convertCountItems("status", counts.GetStatus())...)
+	result.Facets = append(result.Facets, convertCountItems("type", counts.GetType())...)
+	result.Facets = append(result.Facets, convertCountItems("muted", counts.GetMuted())...)
+	result.Facets = append(result.Facets, creatorFacets(creators)...)
+	
+	// Record pagination metadata
+	metadata := resp.GetMetadata()
+	result.Page = metadata.GetPage()
+	result.PageCount = metadata.GetPageCount()
+	result.PerPage = metadata.GetPerPage()
+	result.TotalCount = metadata.GetTotalCount()
+	
+	return result, nil
+}
+
+// SearchAll pages through every monitor matching a monitor search query
+func (c *Client) SearchAll(query string, perPage int64, sortBy string) (*SearchResult, error) {
+	var combined *SearchResult
+	creators := make(map[string]int64)
+	
+	for page := int64(0); ; page++ {
+		result, err := c.Search(query, page, perPage, sortBy)
+		if err != nil {
+			return nil, err
+		}
+		
+		if combined == nil {
+			// Facet counts other than creator cover the whole result set, so keep the first page's
+			combined = result
+		} else {
+			combined.Monitors = append(combined.Monitors, result.Monitors...)
+		}
+		
+		// Creator counts are per page and need to be summed
+		for _, facet := range result.Facets {
+			if facet.Facet == "creator" {
+				creators[facet.Value] += facet.Count
+			}
+		}
+		
+		if len(result.Monitors) == 0 || page+1 >= result.PageCount {
+			break
+		}
+	}
+	
+	// Replace the first page's creator counts with the totals
+	facets := make([]FacetCount, 0, len(combined.Facets))
+	for _, facet := range combined.Facets {
+		if facet.Facet != "creator" {
+			facets = append(facets, facet)
+		}
+	}
+	combined.Facets = append(facets, creatorFacets(creators)...)
+	combined.Page = 0
+	combined.PerPage = int64(len(combined.Monitors))
+	
+	return combined, nil
+}
+
+// Mute mutes a monitor, or a single scope of it, until endTime.
+// Existing silenced scopes are preserved; the resulting silenced map is returned.
+func (c *Client) Mute(monitorID int64, scope string, endTime int64) (map[string]int64, error) {
+	monitor, err := c.getMonitor(monitorID, "")
+	if err != nil {
+		return nil, err
+	}
 	
-	// Set up silencing
-	silenced := make(map[string]int64)
 	if scope == "" {
 		// Mute the entire monitor
-		silenced["*"] = endTime
-	} else {
-		// Mute a specific scope
-		silenced[scope] = endTime
+		scope = "*"
+	}
+	
+	return c.updateSilenced(monitor, []string{scope}, endTime)
+}
+
+// MuteTriggeredGroups mutes every currently triggered group of a monitor individually
+// until endTime. It returns the resulting silenced map and the scopes that were muted.
+func (c *Client) MuteTriggeredGroups(monitorID int64, endTime int64) (map[string]int64, []string, error) {
+	monitor, err := c.getMonitor(monitorID, "alert,warn,no data")
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	// Collect the groups that are not OK
+	scopes := make([]string, 0)
+	for name, group := range monitor.GetState().Groups {
+		if group.GetStatus() != datadogV1.MONITOROVERALLSTATES_OK {
+			scopes = append(scopes, name)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, nil, fmt.Errorf("monitor %d has no triggered groups", monitorID)
+	}
+	sort.Strings(scopes)
+	
+	silenced, err := c.updateSilenced(monitor, scopes, endTime)
+	if err != nil {
+		return nil, nil, err
 	}
 	
-	// Get or create options
+	return silenced, scopes, nil
+}
+
+// Annotate posts a Datadog event recording why a monitor was muted
+func (c *Client) Annotate(monitorID int64, message string, scopes []string, endTime int64) error {
+	eventsAPI := datadogV1.NewEventsApi(c.apiClient)
+	
+	title := fmt.Sprintf("Monitor %d muted", monitorID)
+	text := fmt.Sprintf("%s\n\nScopes: %s\nUntil: %s",
+		message,
+		strings.Join(scopes, "; "),
+		time.Unix(endTime, 0).Format(time.RFC3339))
+	
+	// Create the request body with proper initialization
+	body := *datadogV1.NewEventCreateRequest(text, title)
+	body.SetTags([]string{fmt.Sprintf("monitor_id:%d", monitorID), "source:datadog-cli"})
+	body.SetSourceTypeName("datadog-cli")
+	
+	// Use proper error handling with context
+	_, httpResp, err := eventsAPI.CreateEvent(c.ctx, body)
+	if err != nil {
+		return ddapi.NewAPIError("posting mute event", httpResp, err)
+	}
+	
+	return nil
+}
+
+// bulkConcurrency is the number of monitors updated in parallel by bulk operations
+const bulkConcurrency = 5
+
+// BulkResult is the outcome of a bulk operation on a single monitor
+type BulkResult struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Kind names bulk results in JSON envelopes and schemas
+func (BulkResult) Kind() string {
+	return "MonitorBulkResult"
+}
+
+// runBulk applies op to every monitor concurrently and collects the results in input order.
+// Successful operations are reported with the given result.
+func runBulk(monitors []Monitor, result string, op func(Monitor) error) []BulkResult {
+	results := make([]BulkResult, len(monitors))
+	sem := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+	
+	for i, m := range monitors {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, m Monitor) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			
+			results[i] = BulkResult{ID: m.ID, Name: m.Name, Result: result}
+			if err := op(m); err != nil {
+				results[i].Result = "failed"
+				results[i].Error = err.Error()
+			}
+		}(i, m)
+	}
+	
+	wg.Wait()
+	return results
+}
+
+// getMonitor retrieves a single monitor, optionally including the given group states
+func (c *Client) getMonitor(monitorID int64, groupStates string) (datadogV1.Monitor, error) {
+	monitorsAPI := datadogV1.NewMonitorsApi(c.apiClient)
+	
+	opts := datadogV1.NewGetMonitorOptionalParameters()
+	if groupStates != "" {
+		opts = opts.WithGroupStates(groupStates)
+	}
+	
+	monitor, httpResp, err := monitorsAPI.GetMonitor(c.ctx, monitorID, *opts)
+	if err != nil {
+		return monitor, ddapi.NewAPIError("getting monitor", httpResp, err)
+	}
+	
+	return monitor, nil
+}
+
+// updateSilenced adds scopes to a monitor's existing silenced map and saves the monitor
+func (c *Client) updateSilenced(monitor datadogV1.Monitor, scopes []string, endTime int64) (map[string]int64, error) {
+	monitorsAPI := datadogV1.NewMonitorsApi(c.apiClient)
+	
+	// Merge with the scopes that are already silenced
 	options := monitor.GetOptions()
+	silenced := make(map[string]int64)
+	for scope, until := range options.GetSilenced() {
+		silenced[scope] = until
+	}
+	for _, scope := range scopes {
+		silenced[scope] = endTime
+	}
 	options.SetSilenced(silenced)
 	
+	// Create an update request with proper initialization
+	updateReq := *datadogV1.NewMonitorUpdateRequest()
 	updateReq.SetOptions(options)
 	
 	// Update the monitor
-	_, httpResp, err = monitorsAPI.UpdateMonitor(c.ctx, monitorID, updateReq)
+	updated, httpResp, err := monitorsAPI.UpdateMonitor(c.ctx, monitor.GetId(), updateReq)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error muting monitor (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return fmt.Errorf("error muting monitor: %v", err)
+		err = ddapi.NewAPIError("muting monitor", httpResp, err)
+		c.record("monitor.mute", monitor, nil, err)
+		return nil, err
 	}
 	
-	return nil
+	// Prefer what the API reports, falling back to what we sent
+	updatedOptions := updated.GetOptions()
+	if updatedOptions.HasSilenced() {
+		silenced = updatedOptions.GetSilenced()
+	}
+	c.record("monitor.mute", monitor, silenced, nil)
+	return silenced, nil
 }
 
 // Unmute unmutes a monitor
@@ -129,25 +444,25 @@ func (c *Client) Unmute(monitorID int64, scope string) error {
 	// Get the current monitor
 	monitor, httpResp, err := monitorsAPI.GetMonitor(c.ctx, monitorID)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error getting monitor (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return fmt.Errorf("error getting monitor: %v", err)
+		return ddapi.NewAPIError("getting monitor", httpResp, err)
 	}
 	
 	// Create an update request with proper initialization
 	updateReq := *datadogV1.NewMonitorUpdateRequest()
 	
-	// Get current options and silenced settings
+	// Get current options and silenced settings, leaving the monitor's own
+	// map as it was for the audit log
 	options := monitor.GetOptions()
-	silenced := options.GetSilenced()
+	silenced := make(map[string]int64)
+	for s, until := range options.GetSilenced() {
+		silenced[s] = until
+	}
 	
 	// Remove the silencing based on scope
 	if scope == "" {
 		// Clear all silencing
 		options.SetSilenced(make(map[string]int64))
-	} else if silenced != nil {
+	} else {
 		// Remove specific scope
 		delete(silenced, scope)
 		options.SetSilenced(silenced)
@@ -158,16 +473,33 @@ func (c *Client) Unmute(monitorID int64, scope string) error {
 	// Update the monitor
 	_, httpResp, err = monitorsAPI.UpdateMonitor(c.ctx, monitorID, updateReq)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error unmuting monitor (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return fmt.Errorf("error unmuting monitor: %v", err)
+		err = ddapi.NewAPIError("unmuting monitor", httpResp, err)
+		c.record("monitor.unmute", monitor, nil, err)
+		return err
 	}
 	
+	c.record("monitor.unmute", monitor, options.GetSilenced(), nil)
 	return nil
 }
 
+// record audits a change to a monitor's silenced scopes, made to the monitor
+// as it was before the change
+func (c *Client) record(action string, monitor datadogV1.Monitor, silenced map[string]int64, err error) {
+	options := monitor.GetOptions()
+	before := options.GetSilenced()
+	if before == nil {
+		before = map[string]int64{}
+	}
+	
+	var after interface{}
+	if err == nil {
+		after = map[string]interface{}{"silenced": silenced}
+	}
+	
+	target := fmt.Sprintf("monitor:%d", monitor.GetId())
+	audit.FromContext(c.ctx).Record(c.ctx, action, target, map[string]interface{}{"silenced": before}, after, err)
+}
+
 // Helper function to convert monitor options to a map
 func convertOptions(options datadogV1.MonitorOptions) map[string]interface{} {
 	result := make(map[string]interface{})
@@ -200,3 +532,116 @@ func convertOptions(options datadogV1.MonitorOptions) map[string]interface{} {
 	return result
 }
 
+// convertCountItems converts search count items for a facet to FacetCounts
+func convertCountItems(facet string, items []datadogV1.MonitorSearchCountItem) []FacetCount {
+	result := make([]FacetCount, 0, len(items))
+	for _, item := range items {
+		result = append(result, FacetCount{
+			Facet: facet,
+			Value: fmt.Sprintf("%v", item.GetName()),
+			Count: item.GetCount(),
+		})
+	}
+	return result
+}
+
+// creatorFacets converts creator counts to FacetCounts, most frequent first
+func creatorFacets(creators map[string]int64) []FacetCount {
+	result := make([]FacetCount, 0, len(creators))
+	for handle, count := range creators {
+		result = append(result, FacetCount{Facet: "creator", Value: handle, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// summarizeStatus counts monitors per overall state and collects their triggered groups
+func summarizeStatus(monitors []datadogV1.Monitor, now time.Time) *StatusSummary {
+	counts := make(map[datadogV1.MonitorOverallStates]int)
+	summary := &StatusSummary{}
+	
+	for _, m := range monitors {
+		state := m.GetOverallState()
+		if state == "" || state == datadogV1.MONITOROVERALLSTATES_IGNORED {
+			state = datadogV1.MONITOROVERALLSTATES_UNKNOWN
+		}
+		counts[state]++
+		
+		if state == datadogV1.MONITOROVERALLSTATES_OK ||
+			state == datadogV1.MONITOROVERALLSTATES_SKIPPED ||
+			state == datadogV1.MONITOROVERALLSTATES_UNKNOWN {
+			continue
+		}
+		
+		groups := m.GetState().Groups
+		if len(groups) == 0 {
+			// Simple monitors have no groups; report the monitor as a whole
+			summary.Triggered = append(summary.Triggered, TriggeredGroup{
+				MonitorID: m.GetId(),
+				Monitor:   m.GetName(),
+				Group:     "*",
+				Status:    string(state),
+			})
+			continue
+		}
+		
+		for name, group := range groups {
+			status := group.GetStatus()
+			if status == datadogV1.MONITOROVERALLSTATES_OK {
+				continue
+			}
+			
+			// No Data groups track their own timestamp
+			since := group.GetLastTriggeredTs()
+			if status == datadogV1.MONITOROVERALLSTATES_NO_DATA && group.HasLastNodataTs() {
+				since = group.GetLastNodataTs()
+			}
+			
+			triggered := TriggeredGroup{
+				MonitorID: m.GetId(),
+				Monitor:   m.GetName(),
+				Group:     name,
+				Status:    string(status),
+			}
+			if since > 0 {
+				sinceTime := time.Unix(since, 0)
+				triggered.TriggeredAt = sinceTime.Format(time.RFC3339)
+				triggered.InState = now.Sub(sinceTime).Truncate(time.Second).String()
+			}
+			summary.Triggered = append(summary.Triggered, triggered)
+		}
+	}
+	
+	for _, state := range summaryStates {
+		summary.Counts = append(summary.Counts, StateCount{State: string(state), Count: counts[state]})
+	}
+	
+	// Show the worst and longest-running groups first
+	sort.SliceStable(summary.Triggered, func(i, j int) bool {
+		a, b := summary.Triggered[i], summary.Triggered[j]
+		if a.Status != b.Status {
+			return stateRank(a.Status) < stateRank(b.Status)
+		}
+		if a.TriggeredAt != b.TriggeredAt {
+			return a.TriggeredAt < b.TriggeredAt
+		}
+		return a.MonitorID < b.MonitorID
+	})
+	
+	return summary
+}
+
+// stateRank orders overall states from most to least severe
+func stateRank(state string) int {
+	for i, s := range summaryStates {
+		if string(s) == state {
+			return i
+		}
+	}
+	return len(summaryStates)
+}
@@ -0,0 +1,355 @@
+package monitors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/padawandba/datadog-cli/internal/audit"
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+// rewriteTransport sends every request to a test server
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a Client whose API calls go to handler, and the
+// audit log the client records its changes in
+func newTestClient(t *testing.T, handler http.Handler) (*Client, *audit.Log) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+
+	configuration := datadog.NewConfiguration()
+	configuration.HTTPClient = &http.Client{Transport: rewriteTransport{target: target}}
+	apiClient := datadog.NewAPIClient(configuration)
+
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	recorder := audit.NewRecorder(log, &config.Config{}, nil)
+	return NewClient(apiClient, audit.NewContext(context.Background(), recorder)), log
+}
+
+// monitorAPI is a fake monitors API holding a single monitor. It records the
+// options sent with each update.
+type monitorAPI struct {
+	mu      sync.Mutex
+	monitor map[string]interface{}
+	updates []map[string]interface{}
+}
+
+func (m *monitorAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Method == http.MethodPut {
+		var body map[string]interface{}
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		options, _ := body["options"].(map[string]interface{})
+		m.updates = append(m.updates, options)
+		m.monitor["options"] = options
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.monitor)
+}
+
+// testMonitor returns the JSON of a monitor with silenced scopes and groups
+func testMonitor(silenced map[string]interface{}, groups map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            42,
+		"name":          "CPU high",
+		"type":          "metric alert",
+		"query":         "avg(last_5m):avg:system.cpu.user{*} by {host} > 90",
+		"overall_state": "Alert",
+		"options":       map[string]interface{}{"silenced": silenced},
+		"state":         map[string]interface{}{"groups": groups},
+	}
+}
+
+// silencedScopes returns the sorted scopes of a silenced map in options
+func silencedScopes(options map[string]interface{}) []string {
+	silenced, _ := options["silenced"].(map[string]interface{})
+	scopes := make([]string, 0, len(silenced))
+	for scope := range silenced {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func TestClient_MuteKeepsSilencedScopes(t *testing.T) {
+	endTime := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		mute       func(c *Client) ([]string, error)
+		groups     map[string]interface{}
+		wantScopes []string
+		wantMuted  []string
+	}{
+		{
+			name: "scope",
+			mute: func(c *Client) ([]string, error) {
+				_, err := c.Mute(42, "host:b", endTime)
+				return nil, err
+			},
+			wantScopes: []string{"host:a", "host:b"},
+		},
+		{
+			name: "whole monitor",
+			mute: func(c *Client) ([]string, error) {
+				_, err := c.Mute(42, "", endTime)
+				return nil, err
+			},
+			wantScopes: []string{"*", "host:a"},
+		},
+		{
+			name: "all groups",
+			mute: func(c *Client) ([]string, error) {
+				_, scopes, err := c.MuteTriggeredGroups(42, endTime)
+				return scopes, err
+			},
+			groups: map[string]interface{}{
+				"host:c": map[string]interface{}{"status": "Alert"},
+				"host:d": map[string]interface{}{"status": "OK"},
+				"host:e": map[string]interface{}{"status": "Warn"},
+			},
+			wantScopes: []string{"host:a", "host:c", "host:e"},
+			wantMuted:  []string{"host:c", "host:e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &monitorAPI{monitor: testMonitor(map[string]interface{}{"host:a": 1700000000}, tt.groups)}
+			client, log := newTestClient(t, api)
+
+			muted, err := tt.mute(client)
+			if err != nil {
+				t.Fatalf("mute error = %v", err)
+			}
+
+			if len(api.updates) != 1 {
+				t.Fatalf("monitor updated %d times, want 1", len(api.updates))
+			}
+			sent := api.updates[0]
+			if got := silencedScopes(sent); !equalStrings(got, tt.wantScopes) {
+				t.Errorf("PUT silenced scopes = %v, want %v", got, tt.wantScopes)
+			}
+			if until := sent["silenced"].(map[string]interface{})["host:a"]; until != float64(1700000000) {
+				t.Errorf("PUT changed the end of an existing scope to %v", until)
+			}
+			if !equalStrings(muted, tt.wantMuted) {
+				t.Errorf("muted groups = %v, want %v", muted, tt.wantMuted)
+			}
+
+			// The audit log records the silenced scopes before and after
+			entries, err := log.Read(time.Time{})
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("audit log has %d entries (error %v), want 1", len(entries), err)
+			}
+			entry := entries[0]
+			if entry.Action != "monitor.mute" || entry.Target != "monitor:42" || entry.Result != audit.ResultSuccess {
+				t.Errorf("audit entry = %+v", entry)
+			}
+			before, _ := entry.Before.(map[string]interface{})
+			if got := silencedScopes(before); !equalStrings(got, []string{"host:a"}) {
+				t.Errorf("audit before = %v, want [host:a]", got)
+			}
+			after, _ := entry.After.(map[string]interface{})
+			if got := silencedScopes(after); !equalStrings(got, tt.wantScopes) {
+				t.Errorf("audit after = %v, want %v", got, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestClient_MuteTriggeredGroupsWithoutGroups(t *testing.T) {
+	api := &monitorAPI{monitor: testMonitor(nil, map[string]interface{}{
+		"host:a": map[string]interface{}{"status": "OK"},
+	})}
+	client, _ := newTestClient(t, api)
+
+	if _, _, err := client.MuteTriggeredGroups(42, time.Now().Unix()); err == nil {
+		t.Error("MuteTriggeredGroups() with no triggered groups succeeded, want an error")
+	}
+	if len(api.updates) != 0 {
+		t.Errorf("monitor updated %d times, want 0", len(api.updates))
+	}
+}
+
+func TestSummarizeStatus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ago := func(d time.Duration) int64 { return now.Add(-d).Unix() }
+
+	tests := []struct {
+		name          string
+		monitors      string
+		wantCounts    map[string]int
+		wantTriggered []string
+	}{
+		{
+			name: "groups by severity, then longest in state",
+			monitors: fmt.Sprintf(`[
+				{"id": 1, "name": "disk", "overall_state": "Warn", "state": {"groups": {
+					"host:a": {"status": "Warn", "last_triggered_ts": %d}}}},
+				{"id": 2, "name": "cpu", "overall_state": "Alert", "state": {"groups": {
+					"host:b": {"status": "Alert", "last_triggered_ts": %d},
+					"host:c": {"status": "Alert", "last_triggered_ts": %d},
+					"host:d": {"status": "OK", "last_triggered_ts": %d}}}},
+				{"id": 3, "name": "heartbeat", "overall_state": "No Data", "state": {"groups": {
+					"host:e": {"status": "No Data", "last_triggered_ts": %d, "last_nodata_ts": %d}}}},
+				{"id": 4, "name": "latency", "overall_state": "OK"}
+			]`, ago(10*time.Minute), ago(5*time.Minute), ago(15*time.Minute), ago(time.Hour), ago(time.Minute), ago(20*time.Minute)),
+			wantCounts: map[string]int{"Alert": 1, "Warn": 1, "No Data": 1, "OK": 1},
+			wantTriggered: []string{
+				"2 host:c Alert 15m0s",
+				"2 host:b Alert 5m0s",
+				"1 host:a Warn 10m0s",
+				"3 host:e No Data 20m0s",
+			},
+		},
+		{
+			name: "monitors without groups are reported whole",
+			monitors: `[
+				{"id": 6, "name": "queue", "overall_state": "Alert"},
+				{"id": 5, "name": "errors", "overall_state": "Alert"},
+				{"id": 7, "name": "backup", "overall_state": "Skipped"}
+			]`,
+			wantCounts:    map[string]int{"Alert": 2, "Skipped": 1},
+			wantTriggered: []string{"5 * Alert ", "6 * Alert "},
+		},
+		{
+			name: "ignored and missing states count as unknown",
+			monitors: `[
+				{"id": 8, "name": "ignored", "overall_state": "Ignored"},
+				{"id": 9, "name": "new"}
+			]`,
+			wantCounts: map[string]int{"Unknown": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var monitors []datadogV1.Monitor
+			if err := json.Unmarshal([]byte(withRequiredFields(t, tt.monitors)), &monitors); err != nil {
+				t.Fatalf("invalid fixture: %v", err)
+			}
+
+			summary := summarizeStatus(monitors, now)
+
+			// Every state is counted, in display order
+			if len(summary.Counts) != len(summaryStates) {
+				t.Fatalf("counts = %+v, want one per state", summary.Counts)
+			}
+			for i, count := range summary.Counts {
+				if count.State != string(summaryStates[i]) || count.Count != tt.wantCounts[count.State] {
+					t.Errorf("counts[%d] = %+v, want %s: %d", i, count, summaryStates[i], tt.wantCounts[string(summaryStates[i])])
+				}
+			}
+
+			got := make([]string, len(summary.Triggered))
+			for i, g := range summary.Triggered {
+				got[i] = fmt.Sprintf("%d %s %s %s", g.MonitorID, g.Group, g.Status, g.InState)
+			}
+			if !equalStrings(got, tt.wantTriggered) && len(got)+len(tt.wantTriggered) > 0 {
+				t.Errorf("triggered = %q, want %q", got, tt.wantTriggered)
+			}
+		})
+	}
+}
+
+func TestRunBulk(t *testing.T) {
+	monitors := make([]Monitor, 12)
+	for i := range monitors {
+		monitors[i] = Monitor{ID: int64(i + 1), Name: fmt.Sprintf("monitor-%d", i+1)}
+	}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	results := runBulk(monitors, "muted", func(m Monitor) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		// Later monitors finish first
+		time.Sleep(time.Duration(len(monitors)-int(m.ID)) * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if m.ID%4 == 0 {
+			return fmt.Errorf("monitor %d is locked", m.ID)
+		}
+		return nil
+	})
+
+	if maxRunning > bulkConcurrency {
+		t.Errorf("%d monitors updated at once, want at most %d", maxRunning, bulkConcurrency)
+	}
+	if len(results) != len(monitors) {
+		t.Fatalf("runBulk() returned %d results, want %d", len(results), len(monitors))
+	}
+	for i, result := range results {
+		want := BulkResult{ID: monitors[i].ID, Name: monitors[i].Name, Result: "muted"}
+		if monitors[i].ID%4 == 0 {
+			want.Result = "failed"
+			want.Error = fmt.Sprintf("monitor %d is locked", monitors[i].ID)
+		}
+		if result != want {
+			t.Errorf("results[%d] = %+v, want %+v", i, result, want)
+		}
+	}
+}
+
+// withRequiredFields adds the type and query the API client requires to
+// each monitor of a JSON array
+func withRequiredFields(t *testing.T, monitors string) string {
+	t.Helper()
+	var items []map[string]interface{}
+	if err := json.Unmarshal([]byte(monitors), &items); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+	for _, item := range items {
+		item["type"] = "metric alert"
+		item["query"] = "avg(last_5m):avg:system.cpu.user{*} by {host} > 90"
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// equalStrings reports whether two string slices hold the same values in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,7 +3,9 @@ package monitors
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
@@ -21,8 +23,10 @@ func NewCommands(apiClient *datadog.APIClient, ctx context.Context, cfg *config.
 		Usage: "Manage Datadog monitors",
 		Subcommands: []*cli.Command{
 			listCommand(client, cfg),
-			muteCommand(client),
-			unmuteCommand(client),
+			searchCommand(client, cfg),
+			statusCommand(client, cfg),
+			muteCommand(client, cfg),
+			unmuteCommand(client, cfg),
 		},
 	}
 }
@@ -48,29 +52,247 @@ func listCommand(client *Client, cfg *config.Config) *cli.Command {
 			query := c.String("query")
 			tags := c.StringSlice("tags")
 			
-			monitors, err := client.List(query, tags)
+			formatter, err := console.NewFormatterFromConfig(cfg)
 			if err != nil {
-				return fmt.Errorf("failed to list monitors: %v", err)
+				return err
 			}
 			
-			formatter := console.NewFormatter(cfg.Output)
-			if c.String("output") != "" {
-				formatter = console.NewFormatter(c.String("output"))
+			render := func() error {
+				monitors, err := client.List(query, tags)
+				if err != nil {
+					return fmt.Errorf("failed to list monitors: %w", err)
+				}
+				
+				// Use our custom formatter for monitors
+				return FormatMonitors(formatter, monitors)
 			}
 			
-			// Use our custom formatter for monitors
-			return FormatMonitors(formatter, monitors)
+			if cfg.Watch > 0 {
+				return console.Watch(c.Context, formatter, cfg.Watch, "monitors list", render)
+			}
+			return render()
+		},
+	}
+}
+
+// searchCommand returns the command to search monitors using the monitor search syntax
+func searchCommand(client *Client, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search monitors (e.g., 'status:alert type:metric tag:team:sre priority:1')",
+		ArgsUsage: "QUERY",
+		Flags: []cli.Flag{
+			&cli.Int64Flag{
+				Name:  "page",
+				Usage: "Page of results to return (starting at 0)",
+			},
+			&cli.Int64Flag{
+				Name:  "per-page",
+				Usage: "Number of monitors per page",
+				Value: 30,
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Fetch every page of results",
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: "Sort order (e.g., 'name,asc' or 'status,desc')",
+			},
+			&cli.BoolFlag{
+				Name:  "facets",
+				Usage: "Show facet counts (status, type, muted, creator) over every matching monitor instead of monitors",
+			},
 		},
+		Action: func(c *cli.Context) error {
+			query := strings.Join(c.Args().Slice(), " ")
+			perPage := c.Int64("per-page")
+			sortBy := c.String("sort")
+			
+			// Creator counts are only complete across every page, so facets
+			// always cover the whole result set
+			var result *SearchResult
+			var err error
+			if c.Bool("all") || c.Bool("facets") {
+				result, err = client.SearchAll(query, perPage, sortBy)
+			} else {
+				result, err = client.Search(query, c.Int64("page"), perPage, sortBy)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to search monitors: %w", err)
+			}
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			if c.Bool("facets") {
+				return FormatFacets(formatter.Secondary(), result.Facets)
+			}
+			
+			if c.Bool("all") {
+				formatter.WithTotal(result.TotalCount)
+			} else {
+				formatter.WithPage(result.Page, result.TotalCount)
+			}
+			
+			if err := FormatMonitors(formatter, result.Monitors); err != nil {
+				return err
+			}
+			
+			// Only add the paging summary to human-readable output
+			if formatter.OutFormat == console.TableFormat && !c.Bool("all") {
+				fmt.Printf("\nPage %d of %d (%d monitors total)\n", result.Page+1, result.PageCount, result.TotalCount)
+			}
+			return nil
+		},
+	}
+}
+
+// statusCommand returns the command to summarize monitor states
+func statusCommand(client *Client, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "status",
+		Usage: "Summarize monitor states and list alerting groups",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "tags",
+				Aliases: []string{"t"},
+				Usage:   "Filter monitors by tags",
+			},
+			&cli.DurationFlag{
+				Name:    "watch",
+				Aliases: []string{"w"},
+				Usage:   "Refresh the summary at this interval (e.g., 30s)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			tags := c.StringSlice("tags")
+			interval := c.Duration("watch")
+			if interval <= 0 {
+				interval = cfg.Watch
+			}
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			render := func() error {
+				summary, err := client.Status(tags)
+				if err != nil {
+					return fmt.Errorf("failed to get monitor status: %w", err)
+				}
+				return FormatStatus(formatter, summary)
+			}
+			
+			if interval > 0 {
+				return console.Watch(c.Context, formatter, interval, "monitors status", render)
+			}
+			return render()
+		},
+	}
+}
+
+// selectionFlags returns the flags used to select several monitors for a bulk operation
+func selectionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "tags",
+			Aliases: []string{"t"},
+			Usage:   "Select every monitor matching these tags instead of a single MONITOR_ID",
+		},
+		&cli.StringFlag{
+			Name:  "search",
+			Usage: "Select every monitor matching this monitor search query instead of a single MONITOR_ID",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Skip the confirmation prompt for bulk operations",
+		},
+	}
+}
+
+// isBulkSelection reports whether monitors were selected by tags or search query
+func isBulkSelection(c *cli.Context) bool {
+	return len(c.StringSlice("tags")) > 0 || c.String("search") != ""
+}
+
+// selectMonitors resolves the monitors matching the selection flags, narrowed
+// and ordered by --where, --sort-by and --reverse, and asks the operator to
+// confirm the action unless --yes was given. It returns no monitors if the
+// operator declines.
+func selectMonitors(client *Client, c *cli.Context, formatter *console.Formatter, action string) ([]Monitor, error) {
+	if c.NArg() > 0 {
+		return nil, fmt.Errorf("MONITOR_ID cannot be combined with --tags or --search")
+	}
+	if len(c.StringSlice("tags")) > 0 && c.String("search") != "" {
+		return nil, fmt.Errorf("--tags and --search cannot be used together")
+	}
+	
+	var monitors []Monitor
+	if query := c.String("search"); query != "" {
+		result, err := client.SearchAll(query, 100, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to search monitors: %w", err)
+		}
+		monitors = result.Monitors
+	} else {
+		var err error
+		monitors, err = client.List("", c.StringSlice("tags"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list monitors: %w", err)
+		}
+	}
+	
+	// Act on exactly the monitors that are shown for confirmation
+	selected, err := formatter.ApplyListOptions(monitors)
+	if err != nil {
+		return nil, err
+	}
+	monitors = selected.([]Monitor)
+	
+	if len(monitors) == 0 {
+		fmt.Fprintln(os.Stderr, "No monitors matched the selection")
+		return nil, nil
+	}
+	
+	// The preview goes to stderr so stdout holds only the results. Without a
+	// prompt it is only useful to someone reading, so machine-readable output
+	// skips it.
+	if !c.Bool("yes") || !formatter.OutFormat.MachineReadable() {
+		preview := formatter.WithoutListOptions().WithWriter(os.Stderr)
+		if err := FormatMonitors(preview, monitors); err != nil {
+			return nil, err
+		}
+	}
+	
+	if c.Bool("yes") {
+		return monitors, nil
+	}
+	
+	confirmed, err := console.Confirm(fmt.Sprintf("%s %d monitors?", action, len(monitors)))
+	if err != nil {
+		return nil, err
 	}
+	if !confirmed {
+		fmt.Fprintln(os.Stderr, "Aborted")
+		return nil, nil
+	}
+	
+	return monitors, nil
 }
 
 // muteCommand returns the command to mute a monitor
-func muteCommand(client *Client) *cli.Command {
+func muteCommand(client *Client, cfg *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:      "mute",
-		Usage:     "Mute a monitor",
-		ArgsUsage: "MONITOR_ID",
-		Flags: []cli.Flag{
+		Usage:     "Mute a monitor, or every monitor matching --tags or --search",
+		ArgsUsage: "[MONITOR_ID]",
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:  "scope",
 				Usage: "Scope to mute the monitor for (e.g., 'host:myhost')",
@@ -81,8 +303,78 @@ func muteCommand(client *Client) *cli.Command {
 				Usage:   "Duration to mute the monitor (e.g., 30m, 1h, 2h30m)",
 				Value:   "1h",
 			},
-		},
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Message explaining why the monitor is muted (posted as a Datadog event)",
+			},
+			&cli.BoolFlag{
+				Name:  "all-groups",
+				Usage: "Mute every currently triggered group individually",
+			},
+		}, selectionFlags()...),
 		Action: func(c *cli.Context) error {
+			scope := c.String("scope")
+			durationStr := c.String("duration")
+			message := c.String("message")
+			allGroups := c.Bool("all-groups")
+			
+			duration, err := time.ParseDuration(durationStr)
+			if err != nil {
+				return fmt.Errorf("invalid duration format: %v", err)
+			}
+			
+			if scope != "" && allGroups {
+				return fmt.Errorf("--scope and --all-groups cannot be used together")
+			}
+			
+			endTime := time.Now().Add(duration).Unix()
+			
+			formatter, err := console.NewFormatterFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			
+			// mute mutes a single monitor and returns the resulting silenced map
+			mute := func(monitorID int64) (map[string]int64, error) {
+				var silenced map[string]int64
+				var scopes []string
+				var err error
+				if allGroups {
+					silenced, scopes, err = client.MuteTriggeredGroups(monitorID, endTime)
+				} else {
+					scopes = []string{scope}
+					if scope == "" {
+						scopes = []string{"*"}
+					}
+					silenced, err = client.Mute(monitorID, scope, endTime)
+				}
+				if err != nil {
+					return nil, err
+				}
+				
+				if message != "" {
+					if err := client.Annotate(monitorID, message, scopes, endTime); err != nil {
+						return silenced, fmt.Errorf("monitor muted, but %w", err)
+					}
+				}
+				return silenced, nil
+			}
+			
+			if isBulkSelection(c) {
+				monitors, err := selectMonitors(client, c, formatter, "Mute")
+				if err != nil || len(monitors) == 0 {
+					return err
+				}
+				
+				fmt.Fprintf(os.Stderr, "Muting %d monitors for %s\n", len(monitors), durationStr)
+				results := runBulk(monitors, "muted", func(m Monitor) error {
+					_, err := mute(m.ID)
+					return err
+				})
+				return FormatBulkResults(formatter.Secondary(), results)
+			}
+			
 			if c.NArg() < 1 {
 				return fmt.Errorf("monitor ID argument is required")
 			}
@@ -93,40 +385,60 @@ func muteCommand(client *Client) *cli.Command {
 				return fmt.Errorf("invalid monitor ID: %v", err)
 			}
 			
-			scope := c.String("scope")
-			durationStr := c.String("duration")
-			
-			duration, err := time.ParseDuration(durationStr)
-			if err != nil {
-				return fmt.Errorf("invalid duration format: %v", err)
+			// Progress goes to stderr so stdout holds only the silenced scopes
+			if allGroups {
+				fmt.Fprintf(os.Stderr, "Muting triggered groups of monitor %d for %s\n", monitorID, durationStr)
+			} else {
+				fmt.Fprintf(os.Stderr, "Muting monitor %d", monitorID)
+				if scope != "" {
+					fmt.Fprintf(os.Stderr, " with scope '%s'", scope)
+				}
+				fmt.Fprintf(os.Stderr, " for %s\n", durationStr)
 			}
 			
-			endTime := time.Now().Add(duration).Unix()
-			
-			fmt.Printf("Muting monitor %d", monitorID)
-			if scope != "" {
-				fmt.Printf(" with scope '%s'", scope)
+			silenced, err := mute(monitorID)
+			if err != nil {
+				return err
 			}
-			fmt.Printf(" for %s\n", durationStr)
 			
-			return client.Mute(monitorID, scope, endTime)
+			return FormatSilenced(formatter, silenced)
 		},
 	}
 }
 
 // unmuteCommand returns the command to unmute a monitor
-func unmuteCommand(client *Client) *cli.Command {
+func unmuteCommand(client *Client, cfg *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:      "unmute",
-		Usage:     "Unmute a monitor",
-		ArgsUsage: "MONITOR_ID",
-		Flags: []cli.Flag{
+		Usage:     "Unmute a monitor, or every monitor matching --tags or --search",
+		ArgsUsage: "[MONITOR_ID]",
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:  "scope",
 				Usage: "Scope to unmute the monitor for (e.g., 'host:myhost')",
 			},
-		},
+		}, selectionFlags()...),
 		Action: func(c *cli.Context) error {
+			scope := c.String("scope")
+			
+			if isBulkSelection(c) {
+				formatter, err := console.NewFormatterFromConfig(cfg)
+				if err != nil {
+					return err
+				}
+				
+				monitors, err := selectMonitors(client, c, formatter, "Unmute")
+				if err != nil || len(monitors) == 0 {
+					return err
+				}
+				
+				fmt.Fprintf(os.Stderr, "Unmuting %d monitors\n", len(monitors))
+				results := runBulk(monitors, "unmuted", func(m Monitor) error {
+					return client.Unmute(m.ID, scope)
+				})
+				return FormatBulkResults(formatter.Secondary(), results)
+			}
+			
 			if c.NArg() < 1 {
 				return fmt.Errorf("monitor ID argument is required")
 			}
@@ -137,15 +449,14 @@ func unmuteCommand(client *Client) *cli.Command {
 				return fmt.Errorf("invalid monitor ID: %v", err)
 			}
 			
-			scope := c.String("scope")
-			
-			fmt.Printf("Unmuting monitor %d", monitorID)
+			// Progress goes to stderr, like the other mute and unmute commands
+			fmt.Fprintf(os.Stderr, "Unmuting monitor %d", monitorID)
 			if scope != "" {
-				fmt.Printf(" with scope '%s'", scope)
+				fmt.Fprintf(os.Stderr, " with scope '%s'", scope)
 			}
-			fmt.Println()
+			fmt.Fprintln(os.Stderr)
 			
 			return client.Unmute(monitorID, scope)
 		},
 	}
-}
\ No newline at end of file
+}
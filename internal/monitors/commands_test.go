@@ -0,0 +1,177 @@
+package monitors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
+	"github.com/urfave/cli/v2"
+)
+
+// fleetAPI is a fake monitors API holding several monitors. Updates to the
+// monitors in fail are rejected.
+type fleetAPI struct {
+	mu       sync.Mutex
+	monitors []map[string]interface{}
+	fail     map[string]bool
+	updated  []string
+}
+
+func (f *fleetAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/api/v1/monitor" {
+		json.NewEncoder(w).Encode(f.monitors)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/monitor/")
+	for _, m := range f.monitors {
+		if m["id"] != json.Number(id) {
+			continue
+		}
+		if r.Method == http.MethodPut {
+			if f.fail[id] {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"errors": ["monitor is locked"]}`)
+				return
+			}
+			f.updated = append(f.updated, id)
+		}
+		json.NewEncoder(w).Encode(m)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	io.WriteString(w, `{"errors": ["not found"]}`)
+}
+
+// newFleetAPI returns a fake API with monitors given as id, name and status
+func newFleetAPI(t *testing.T, monitors ...[3]string) *fleetAPI {
+	t.Helper()
+	api := &fleetAPI{fail: map[string]bool{}}
+	for _, m := range monitors {
+		api.monitors = append(api.monitors, map[string]interface{}{
+			"id":            json.Number(m[0]),
+			"name":          m[1],
+			"overall_state": m[2],
+			"type":          "metric alert",
+			"query":         "avg(last_5m):avg:system.cpu.user{*} > 90",
+			"tags":          []string{"team:x"},
+		})
+	}
+	return api
+}
+
+// runCommand runs a command with args and returns what it wrote to stdout
+func runCommand(t *testing.T, command *cli.Command, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	app := &cli.App{Name: "dd", Commands: []*cli.Command{command}, ExitErrHandler: func(*cli.Context, error) {}}
+	runErr := app.Run(append([]string{"dd", command.Name}, args...))
+	w.Close()
+	return <-out, runErr
+}
+
+func TestMuteCommand_BulkSelection(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		args        []string
+		fail        []string
+		wantUpdated []string
+		wantResults []string
+		wantExit    int
+	}{
+		{
+			name:        "where narrows the targets",
+			cfg:         config.Config{Output: "json", Where: `status == "Alert"`},
+			args:        []string{"--tags", "team:x", "--yes"},
+			wantUpdated: []string{"1", "3"},
+			wantResults: []string{"1 muted", "3 muted"},
+		},
+		{
+			name:        "sort-by orders the results",
+			cfg:         config.Config{Output: "json", SortBy: "name", Reverse: true},
+			args:        []string{"--tags", "team:x", "--yes"},
+			wantUpdated: []string{"1", "2", "3"},
+			wantResults: []string{"2 muted", "1 muted", "3 muted"},
+		},
+		{
+			name:        "partial failure",
+			cfg:         config.Config{Output: "json"},
+			args:        []string{"--tags", "team:x", "--yes"},
+			fail:        []string{"2"},
+			wantUpdated: []string{"1", "3"},
+			wantResults: []string{"1 muted", "2 failed", "3 muted"},
+			wantExit:    ddapi.ExitPartialFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFleetAPI(t,
+				[3]string{"1", "cpu", "Alert"},
+				[3]string{"2", "disk", "OK"},
+				[3]string{"3", "api errors", "Alert"},
+			)
+			for _, id := range tt.fail {
+				api.fail[id] = true
+			}
+			client, _ := newTestClient(t, api)
+
+			out, err := runCommand(t, muteCommand(client, &tt.cfg), tt.args...)
+			if tt.wantExit == 0 && err != nil {
+				t.Fatalf("mute error = %v", err)
+			}
+			if tt.wantExit != 0 {
+				var partial *ddapi.PartialFailureError
+				if !errors.As(err, &partial) || ddapi.ExitCode(err) != tt.wantExit {
+					t.Fatalf("mute error = %v, want a partial failure with exit code %d", err, tt.wantExit)
+				}
+			}
+
+			updated := append([]string(nil), api.updated...)
+			sort.Strings(updated)
+			if !equalStrings(updated, tt.wantUpdated) {
+				t.Errorf("updated monitors = %v, want %v", updated, tt.wantUpdated)
+			}
+
+			// Stdout holds only the results document
+			var results []BulkResult
+			if err := json.Unmarshal([]byte(out), &results); err != nil {
+				t.Fatalf("stdout is not a JSON results document: %v\n%s", err, out)
+			}
+			got := make([]string, len(results))
+			for i, result := range results {
+				got[i] = fmt.Sprintf("%d %s", result.ID, result.Result)
+			}
+			if !equalStrings(got, tt.wantResults) {
+				t.Errorf("results = %v, want %v", got, tt.wantResults)
+			}
+		})
+	}
+}
@@ -2,11 +2,13 @@ package monitors
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	"github.com/padawandba/datadog-cli/internal/platform/console"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
 )
 
 // FormatMonitors formats a slice of Monitor structs for display
@@ -16,6 +18,88 @@ func FormatMonitors(formatter *console.Formatter, monitors []Monitor) error {
 	return formatter.Format(monitors)
 }
 
+// FormatFacets formats monitor search facet counts for display
+func FormatFacets(formatter *console.Formatter, facets []FacetCount) error {
+	return formatter.Format(facets)
+}
+
+// FormatStatus formats a monitor status summary for display
+func FormatStatus(formatter *console.Formatter, summary *StatusSummary) error {
+	switch formatter.OutFormat {
+	case console.JSONFormat, console.YAMLFormat, console.NDJSONFormat,
+		console.GoTemplateFormat, console.JSONPathFormat:
+		// Document formats get the whole summary as a single document
+		return formatter.Format(summary)
+	case console.CSVFormat, console.TSVFormat:
+		// Delimited output holds one table: the alerting groups
+		return formatter.Format(summary.Triggered)
+	}
+	
+	// --columns, --where and --sort-by describe the alerting monitors
+	return formatter.FormatSections(
+		console.Section{Title: "Monitor states", Data: summary.Counts, Secondary: true},
+		console.Section{Title: "Alerting monitors", Data: summary.Triggered, Empty: "No monitors are alerting"},
+	)
+}
+
+// FormatBulkResults formats the per-monitor results of a bulk operation and
+// returns an error if any monitor failed
+func FormatBulkResults(formatter *console.Formatter, results []BulkResult) error {
+	if err := formatter.Format(results); err != nil {
+		return err
+	}
+	
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return &ddapi.PartialFailureError{Failed: failed, Total: len(results), Noun: "monitors"}
+	}
+	return nil
+}
+
+// SilencedScope is a single muted scope of a monitor
+type SilencedScope struct {
+	Scope string `json:"scope"`
+	Until string `json:"until"`
+}
+
+// Kind names silenced scopes in JSON envelopes and schemas
+func (SilencedScope) Kind() string {
+	return "SilencedScope"
+}
+
+// FormatSilenced formats a monitor's silenced map for display
+func FormatSilenced(formatter *console.Formatter, silenced map[string]int64) error {
+	return formatter.Format(SilencedScopes(silenced))
+}
+
+// SilencedScopes converts a monitor's silenced map to scopes sorted by name
+func SilencedScopes(silenced map[string]int64) []SilencedScope {
+	scopes := make([]SilencedScope, 0, len(silenced))
+	for scope, until := range silenced {
+		scopes = append(scopes, SilencedScope{
+			Scope: scope,
+			Until: formatUntil(until),
+		})
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		return scopes[i].Scope < scopes[j].Scope
+	})
+	return scopes
+}
+
+// formatUntil formats a silenced end timestamp
+func formatUntil(until int64) string {
+	if until > 0 {
+		return time.Unix(until, 0).Format(time.RFC3339)
+	}
+	return "indefinitely"
+}
+
 // FormatAPIMonitors formats a slice of Datadog API Monitor structs for display
 func FormatAPIMonitors(formatter *console.Formatter, monitors []datadogV1.Monitor) error {
 	// Convert the monitors to our simplified format for display
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 )
 
 // Common errors
@@ -22,14 +24,90 @@ type Config struct {
 	AppKey string `json:"dd_app_key"`
 	Site   string `json:"dd_site"`
 	Output string `json:"output"`
+	
+	// LogSpoolDir is where logs are kept until they are delivered to Datadog;
+	// empty disables the spool
+	LogSpoolDir string `json:"log_spool_dir,omitempty"`
+	
+	// RedactPatterns are regular expressions whose matches are masked in logs,
+	// and RedactKeys are log attribute keys whose values are masked
+	RedactPatterns []string `json:"redact_patterns,omitempty"`
+	RedactKeys     []string `json:"redact_keys,omitempty"`
+	
+	// LogLevel is the minimum level of logs (debug, info, warn or error) and
+	// LogFormat the format of local logs (text or json). LogFile receives the
+	// local logs instead of stderr.
+	LogLevel  string `json:"log_level,omitempty"`
+	LogFormat string `json:"log_format,omitempty"`
+	LogFile   string `json:"log_file,omitempty"`
+	
+	// NoRemoteLogs turns off sending logs to the log sink
+	NoRemoteLogs bool `json:"no_remote_logs,omitempty"`
+	
+	// LogSink selects where logs are sent: datadog (the default), otlp or file.
+	// LogSinkPath is the file of the file sink and LogOTLPEndpoint the URL of
+	// the otlp sink.
+	LogSink         string `json:"log_sink,omitempty"`
+	LogSinkPath     string `json:"log_sink_path,omitempty"`
+	LogOTLPEndpoint string `json:"log_otlp_endpoint,omitempty"`
+	
+	// AuditLog is the file changes made with the CLI are recorded in; empty
+	// uses audit.jsonl next to this file. AuditEvents also posts each change
+	// as a Datadog event.
+	AuditLog    string `json:"audit_log,omitempty"`
+	AuditEvents bool   `json:"audit_events,omitempty"`
+	
+	// Metrics selects where command and API call metrics are sent: dogstatsd,
+	// api or off (the default). DogStatsDAddr is the DogStatsD address of the
+	// dogstatsd destination.
+	Metrics       string `json:"metrics,omitempty"`
+	DogStatsDAddr string `json:"dogstatsd_addr,omitempty"`
+	
+	// Traces sends a trace of each command and its API calls to the Datadog
+	// Agent at TraceAgentURL
+	Traces        bool   `json:"traces,omitempty"`
+	TraceAgentURL string `json:"trace_agent_url,omitempty"`
+	
+	// HTTPBodyLimit is the number of bytes of each request and response body
+	// written by --trace-http
+	HTTPBodyLimit int `json:"http_body_limit,omitempty"`
+	
+	// TraceHTTP writes each API request and response with local logs; set from
+	// --trace-http and --debug only
+	TraceHTTP bool `json:"-"`
+	
+	// TemplateFile is a go-template file used for output; set from flags only
+	TemplateFile string `json:"-"`
+	
+	// Columns selects the columns of table and delimited output; set from flags only
+	Columns []string `json:"-"`
+	
+	// SortBy, Reverse and Where sort and filter list output; set from flags only
+	SortBy  string `json:"-"`
+	Reverse bool   `json:"-"`
+	Where   string `json:"-"`
+	
+	// NoColor disables colored table output; set from flags only
+	NoColor bool `json:"-"`
+	
+	// JSONEnvelope wraps JSON and YAML output in a versioned envelope; set from flags only
+	JSONEnvelope bool `json:"-"`
+	
+	// Profile names the configuration in use, reported in envelope metadata.
+	// The CLI currently has a single profile, "default".
+	Profile string `json:"-"`
+	
+	// Watch re-runs list commands at this interval; set from flags only
+	Watch time.Duration `json:"-"`
 }
 
 // Load loads configuration from config file and environment variables
 func Load() (*Config, error) {
 	// Initialize with default values
 	config := &Config{
-		Site:   "datadoghq.com", // The 'api.' prefix will be added by the client
-		Output: "table",
+		Site:    "datadoghq.com", // The 'api.' prefix will be added by the client
+		Output:  "table",
+		Profile: "default",
 	}
 
 	// Try to load from config file
@@ -63,6 +141,26 @@ func Load() (*Config, error) {
 		config.Site = site
 		envLoaded = true
 	}
+	if spoolDir := os.Getenv("DD_LOG_SPOOL_DIR"); spoolDir != "" {
+		config.LogSpoolDir = spoolDir
+		envLoaded = true
+	}
+	if sink := os.Getenv("DD_LOG_SINK"); sink != "" {
+		config.LogSink = sink
+		envLoaded = true
+	}
+	if sinkPath := os.Getenv("DD_LOG_SINK_PATH"); sinkPath != "" {
+		config.LogSinkPath = sinkPath
+		envLoaded = true
+	}
+	if auditLog := os.Getenv("DD_AUDIT_LOG"); auditLog != "" {
+		config.AuditLog = auditLog
+		envLoaded = true
+	}
+	if auditEvents := os.Getenv("DD_AUDIT_EVENTS"); auditEvents != "" {
+		config.AuditEvents, _ = strconv.ParseBool(auditEvents)
+		envLoaded = true
+	}
 	
 	if envLoaded {
 		slog.Debug("Applied environment variable configuration")
@@ -0,0 +1,157 @@
+package console
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefaultColumner is implemented by row types that declare the narrow set of
+// columns shown in table output by default. Wide output (-o wide) and an
+// explicit column selection (--columns) override it.
+type DefaultColumner interface {
+	DefaultColumns() []string
+}
+
+// defaultColumnerType is the reflect.Type of the DefaultColumner interface
+var defaultColumnerType = reflect.TypeOf((*DefaultColumner)(nil)).Elem()
+
+// selectColumns returns the headers and field indices to display for a struct
+// type. Explicitly selected columns win; otherwise, when narrow is true and the
+// type declares default columns, those are used; otherwise every field is shown.
+func (f *Formatter) selectColumns(structType reflect.Type, narrow bool) ([]string, []int, error) {
+	headers, fieldIndices := structFields(structType)
+	
+	selected := f.Columns
+	if len(selected) == 0 && narrow && !f.Wide && structType.Implements(defaultColumnerType) {
+		selected = reflect.Zero(structType).Interface().(DefaultColumner).DefaultColumns()
+	}
+	if len(selected) == 0 {
+		return headers, fieldIndices, nil
+	}
+	
+	// Index the available columns by json name
+	available := make(map[string]int, len(headers))
+	for i, header := range headers {
+		available[header] = fieldIndices[i]
+	}
+	
+	selectedHeaders := make([]string, 0, len(selected))
+	selectedIndices := make([]int, 0, len(selected))
+	for _, name := range selected {
+		name = strings.TrimSpace(name)
+		fieldIdx, ok := available[name]
+		if !ok {
+			// Secondary tables show whichever selected columns they have
+			if f.secondary {
+				continue
+			}
+			return nil, nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(headers, ", "))
+		}
+		selectedHeaders = append(selectedHeaders, name)
+		selectedIndices = append(selectedIndices, fieldIdx)
+	}
+	
+	// A secondary table with none of the selected columns shows its defaults
+	if f.secondary && len(selectedHeaders) == 0 {
+		plain := *f
+		plain.Columns = nil
+		return plain.selectColumns(structType, narrow)
+	}
+	
+	return selectedHeaders, selectedIndices, nil
+}
+
+// Secondary returns a copy of the formatter for a table that accompanies a
+// command's primary list, such as summary counts or per-item results. The
+// list options do not apply to it, and selected columns it lacks are ignored
+// instead of failing the command.
+func (f *Formatter) Secondary() *Formatter {
+	secondary := f.WithoutListOptions()
+	secondary.secondary = true
+	return secondary
+}
+
+// customColumn is a single column of custom-columns output
+type customColumn struct {
+	header string
+	path   []jsonPathStep
+}
+
+// parseCustomColumns parses a custom-columns spec such as
+// "NAME:.name,MUTED:.is_muted"; paths use JSONPath syntax
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	columns := make([]customColumn, 0)
+	
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		
+		header, expr, ok := strings.Cut(part, ":")
+		if !ok || header == "" || expr == "" {
+			return nil, fmt.Errorf("invalid custom column %q (expected HEADER:.path)", part)
+		}
+		
+		expr = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(expr), "{"), "}")
+		path, err := parseJSONPathExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, customColumn{header: header, path: path})
+	}
+	
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("custom-columns requires at least one HEADER:.path column")
+	}
+	return columns, nil
+}
+
+// formatCustomColumns formats data as a table with user-defined columns.
+// Each element of a slice is one row; anything else is a single row.
+func (f *Formatter) formatCustomColumns(data interface{}) error {
+	columns, err := parseCustomColumns(f.Template)
+	if err != nil {
+		return err
+	}
+	
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+	
+	items, ok := generic.([]interface{})
+	if !ok {
+		items = []interface{}{generic}
+	}
+	
+	w := f.createTabWriter()
+	defer w.Flush()
+	
+	if f.TableOptions.Header {
+		headers := make([]string, len(columns))
+		for i, column := range columns {
+			headers[i] = column.header
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+	
+	for _, item := range items {
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			results := evalJSONPath(column.path, item, item)
+			parts := make([]string, 0, len(results))
+			for _, result := range results {
+				parts = append(parts, jsonPathString(result))
+			}
+			values[i] = f.cellValue(strings.Join(parts, ","))
+			if values[i] == "" {
+				values[i] = "<none>"
+			}
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	
+	return nil
+}
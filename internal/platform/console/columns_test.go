@@ -0,0 +1,71 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type columnsRow struct {
+	Name    string   `json:"name"`
+	Up      bool     `json:"up"`
+	IsMuted bool     `json:"is_muted"`
+	Apps    []string `json:"apps"`
+}
+
+func (columnsRow) DefaultColumns() []string {
+	return []string{"name", "up"}
+}
+
+func TestFormatter_Columns(t *testing.T) {
+	rows := []columnsRow{
+		{Name: "web-01", Up: true, Apps: []string{"nginx"}},
+		{Name: "db-01", IsMuted: true},
+	}
+
+	tests := []struct {
+		name       string
+		output     string
+		columns    []string
+		secondary  bool
+		wantHeader string
+		wantErr    bool
+	}{
+		{name: "default columns", output: "table", wantHeader: "name up"},
+		{name: "wide", output: "wide", wantHeader: "name up is_muted apps"},
+		{name: "explicit columns", output: "table", columns: []string{"is_muted", "name"}, wantHeader: "is_muted name"},
+		{name: "delimited shows every column", output: "csv", wantHeader: "name,up,is_muted,apps"},
+		{name: "custom columns", output: "custom-columns=HOST:.name,MUTED:.is_muted", wantHeader: "HOST MUTED"},
+		{name: "unknown column", output: "table", columns: []string{"bogus"}, wantErr: true},
+		{name: "secondary skips unknown columns", output: "table", columns: []string{"bogus", "is_muted"}, secondary: true, wantHeader: "is_muted"},
+		{name: "secondary falls back to defaults", output: "table", columns: []string{"bogus"}, secondary: true, wantHeader: "name up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.output)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			f.Columns = tt.columns
+			if tt.secondary {
+				f = f.Secondary()
+			}
+			var buf bytes.Buffer
+			f.WithWriter(&buf)
+
+			err = f.Format(rows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			header := strings.Join(strings.Fields(strings.SplitN(buf.String(), "\n", 2)[0]), " ")
+			if header != tt.wantHeader {
+				t.Errorf("header = %q, want %q", header, tt.wantHeader)
+			}
+		})
+	}
+}
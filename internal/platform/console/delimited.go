@@ -0,0 +1,203 @@
+package console
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// formatDelimited formats data as delimiter-separated values with a header row
+func (f *Formatter) formatDelimited(data interface{}, comma rune) error {
+	headers, rows, err := f.records(data, false)
+	if err != nil {
+		return err
+	}
+	
+	w := csv.NewWriter(f.Writer)
+	w.Comma = comma
+	
+	if f.TableOptions.Header {
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	
+	w.Flush()
+	return w.Error()
+}
+
+// formatNDJSON formats data as newline-delimited JSON, one object per slice element
+func (f *Formatter) formatNDJSON(data interface{}) error {
+	encoder := json.NewEncoder(f.Writer)
+	
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	
+	// Non-collections are written as a single line
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return encoder.Encode(data)
+	}
+	
+	for i := 0; i < v.Len(); i++ {
+		if err := encoder.Encode(v.Index(i).Interface()); err != nil {
+			return fmt.Errorf("error encoding to NDJSON: %v", err)
+		}
+	}
+	return nil
+}
+
+// records flattens data into a header row and string rows. Slices of structs
+// produce one row per element with a column per selected field, using the
+// same json-tag naming as table output; single structs produce one row; maps
+// produce KEY/VALUE rows; anything else produces a single VALUE column.
+// When narrow is true, types implementing DefaultColumner show their default columns.
+func (f *Formatter) records(data interface{}, narrow bool) ([]string, [][]string, error) {
+	v := reflect.ValueOf(data)
+	if !v.IsValid() {
+		return []string{"VALUE"}, nil, nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return []string{"VALUE"}, nil, nil
+		}
+		v = v.Elem()
+	}
+	
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType := v.Type().Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+		
+		if elemType.Kind() != reflect.Struct || isScalarStruct(elemType) {
+			rows := make([][]string, 0, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				rows = append(rows, []string{flattenValue(v.Index(i))})
+			}
+			return []string{"VALUE"}, rows, nil
+		}
+		
+		headers, fieldIndices, err := f.selectColumns(elemType, narrow)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows := make([][]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			rows = append(rows, structRow(v.Index(i), fieldIndices))
+		}
+		return headers, rows, nil
+		
+	case reflect.Struct:
+		if isScalarStruct(v.Type()) {
+			return []string{"VALUE"}, [][]string{{flattenValue(v)}}, nil
+		}
+		headers, fieldIndices, err := f.selectColumns(v.Type(), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		return headers, [][]string{structRow(v, fieldIndices)}, nil
+		
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return flattenValue(keys[i]) < flattenValue(keys[j])
+		})
+		rows := make([][]string, 0, len(keys))
+		for _, key := range keys {
+			rows = append(rows, []string{flattenValue(key), flattenValue(v.MapIndex(key))})
+		}
+		return []string{"KEY", "VALUE"}, rows, nil
+		
+	default:
+		return []string{"VALUE"}, [][]string{{flattenValue(v)}}, nil
+	}
+}
+
+// structRow flattens the given fields of a struct (or struct pointer) value.
+// Nil pointers produce empty cells.
+func structRow(v reflect.Value, fieldIndices []int) []string {
+	row := make([]string, len(fieldIndices))
+	
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return row
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return row
+	}
+	
+	for j, fieldIdx := range fieldIndices {
+		if fieldIdx < v.NumField() {
+			row[j] = flattenValue(v.Field(fieldIdx))
+		}
+	}
+	return row
+}
+
+// isScalarStruct reports whether a struct type should be rendered as a single value
+func isScalarStruct(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{})
+}
+
+// flattenValue renders a value as a single cell: slices become comma-separated
+// lists, maps become sorted key=value lists, and nil values become empty strings
+func flattenValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return flattenValue(v.Elem())
+		
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("%s", v.Interface())
+		}
+		parts := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts[i] = flattenValue(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+		
+	case reflect.Map:
+		parts := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			parts = append(parts, flattenValue(iter.Key())+"="+flattenValue(iter.Value()))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+		
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339)
+		}
+		// Nested structs are embedded as compact JSON
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprintf("%v", v.Interface())
+		}
+		return string(data)
+		
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
@@ -0,0 +1,110 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testRow struct {
+	Name   string         `json:"name"`
+	Tags   []string       `json:"tags"`
+	Counts map[string]int `json:"counts"`
+	Up     bool           `json:"up"`
+	Hidden string         `json:"-"`
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{"", TableFormat, false},
+		{"table", TableFormat, false},
+		{"JSON", JSONFormat, false},
+		{"csv", CSVFormat, false},
+		{"tsv", TSVFormat, false},
+		{"ndjson", NDJSONFormat, false},
+		{"markdown", MarkdownFormat, false},
+		{"md", MarkdownFormat, false},
+		{"html", HTMLFormat, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := ParseOutputFormat(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutputFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOutputFormat(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatter_Delimited(t *testing.T) {
+	rows := []testRow{
+		{Name: `web,"01"`, Tags: []string{"env:prod", "role:web"}, Counts: map[string]int{"b": 2, "a": 1}, Up: true, Hidden: "secret"},
+		{Name: "db"},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "csv",
+			want: "name,tags,counts,up\n" +
+				"\"web,\"\"01\"\"\",\"env:prod,role:web\",\"a=1,b=2\",true\n" +
+				"db,,,false\n",
+		},
+		{
+			format: "tsv",
+			want: "name\ttags\tcounts\tup\n" +
+				"\"web,\"\"01\"\"\"\tenv:prod,role:web\ta=1,b=2\ttrue\n" +
+				"db\t\t\tfalse\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			f, err := NewFormatter(tt.format)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			var buf bytes.Buffer
+			f.WithWriter(&buf)
+
+			if err := f.Format(rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatter_NDJSON(t *testing.T) {
+	f, err := NewFormatter("ndjson")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	if err := f.Format([]testRow{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], `{"name":"a"`) || !strings.HasPrefix(lines[1], `{"name":"b"`) {
+		t.Errorf("unexpected NDJSON output: %q", buf.String())
+	}
+}
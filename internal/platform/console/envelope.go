@@ -0,0 +1,109 @@
+package console
+
+import (
+	"reflect"
+	"time"
+)
+
+// EnvelopeAPIVersion is the version of the JSON envelope and of the item
+// schemas published under schemas/. It changes only for breaking changes.
+const EnvelopeAPIVersion = "datadog-cli/v1"
+
+// Kinder is implemented by output types to name their kind in JSON envelopes
+// and published schemas (e.g., "Host" for a HostList envelope)
+type Kinder interface {
+	Kind() string
+}
+
+// Envelope wraps JSON and YAML output with its kind and metadata when
+// --json-envelope is set
+type Envelope struct {
+	APIVersion string           `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string           `json:"kind" yaml:"kind"`
+	Items      interface{}      `json:"items" yaml:"items"`
+	Metadata   EnvelopeMetadata `json:"metadata" yaml:"metadata"`
+}
+
+// EnvelopeMetadata describes where and when an envelope was produced
+type EnvelopeMetadata struct {
+	Site        string    `json:"site" yaml:"site"`
+	Profile     string    `json:"profile" yaml:"profile"`
+	Total       int64     `json:"total" yaml:"total"`
+	Page        *int64    `json:"page,omitempty" yaml:"page,omitempty"`
+	GeneratedAt time.Time `json:"generated_at" yaml:"generated_at"`
+}
+
+// WithPage records the page being shown and the total number of items across
+// all pages for the envelope metadata, and returns the formatter
+func (f *Formatter) WithPage(page, total int64) *Formatter {
+	f.page = &page
+	f.total = &total
+	return f
+}
+
+// WithTotal records the total number of items across all pages for the
+// envelope metadata, and returns the formatter
+func (f *Formatter) WithTotal(total int64) *Formatter {
+	f.total = &total
+	return f
+}
+
+// envelope wraps data in an Envelope. Items is always a list: a single
+// object becomes a list of one.
+func (f *Formatter) envelope(data interface{}) Envelope {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	
+	var items interface{}
+	var itemType reflect.Type
+	var count int64
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		itemType = v.Type().Elem()
+		count = int64(v.Len())
+		// Encode empty lists as [] rather than null
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			v = reflect.MakeSlice(v.Type(), 0, 0)
+		}
+		items = v.Interface()
+	} else {
+		itemType = v.Type()
+		count = 1
+		items = []interface{}{v.Interface()}
+	}
+	
+	metadata := EnvelopeMetadata{
+		Site:        f.Site,
+		Profile:     f.Profile,
+		Total:       count,
+		Page:        f.page,
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	// Server-side totals no longer apply once items are filtered locally
+	if f.total != nil && f.Where == "" {
+		metadata.Total = *f.total
+	}
+	
+	return Envelope{
+		APIVersion: EnvelopeAPIVersion,
+		Kind:       KindOf(itemType) + "List",
+		Items:      items,
+		Metadata:   metadata,
+	}
+}
+
+// KindOf returns the kind of an output type: its Kind method if it
+// implements Kinder, otherwise its Go type name
+func KindOf(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if kinder, ok := reflect.Zero(t).Interface().(Kinder); ok {
+		return kinder.Kind()
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return "Item"
+}
@@ -0,0 +1,573 @@
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ApplyListOptions filters and sorts a slice according to the formatter's
+// Where, SortBy and Reverse settings. Fields are addressed by their JSON
+// names, so the same expressions work for every resource. Anything other
+// than a slice is returned unchanged. Commands that act on a listing call it
+// directly so they act on exactly the rows that are displayed.
+func (f *Formatter) ApplyListOptions(data interface{}) (interface{}, error) {
+	if f.Where == "" && f.SortBy == "" && !f.Reverse {
+		return data, nil
+	}
+	
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return data, nil
+	}
+	
+	var where expression
+	if f.Where != "" {
+		var err error
+		where, err = parseExpression(f.Where)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	var sortKeys []string
+	if f.SortBy != "" {
+		for _, key := range strings.Split(f.SortBy, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				sortKeys = append(sortKeys, key)
+			}
+		}
+	}
+	
+	// Pair each element with its generic JSON form for field lookups
+	type item struct {
+		value   reflect.Value
+		generic interface{}
+	}
+	items := make([]item, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		generic, err := toGeneric(elem.Interface())
+		if err != nil {
+			return nil, err
+		}
+		
+		if where != nil {
+			matched, err := where.eval(generic)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --where expression: %v", err)
+			}
+			if !truthy(matched) {
+				continue
+			}
+		}
+		items = append(items, item{value: elem, generic: generic})
+	}
+	
+	if len(sortKeys) > 0 {
+		sort.SliceStable(items, func(i, j int) bool {
+			for _, key := range sortKeys {
+				a := lookupField(items[i].generic, key)
+				b := lookupField(items[j].generic, key)
+				
+				// Missing values always sort last
+				if a == nil || b == nil {
+					if (a == nil) != (b == nil) {
+						return b == nil
+					}
+					continue
+				}
+				
+				if c, err := compareValues(a, b); err == nil && c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+	}
+	
+	if f.Reverse {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	
+	result := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, len(items))
+	for _, it := range items {
+		result = reflect.Append(result, it.value)
+	}
+	return result.Interface(), nil
+}
+
+// WithoutListOptions returns a copy of the formatter that displays data as
+// given, for data that was already filtered with ApplyListOptions or that the
+// list options do not describe
+func (f *Formatter) WithoutListOptions() *Formatter {
+	plain := *f
+	plain.Where = ""
+	plain.SortBy = ""
+	plain.Reverse = false
+	return &plain
+}
+
+// lookupField returns the value of a dotted JSON field path, or nil if missing
+func lookupField(value interface{}, path string) interface{} {
+	for _, name := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[name]
+	}
+	return value
+}
+
+// truthy reports whether an expression result counts as true
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f != 0
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// nowPattern matches relative time literals such as "now" or "now-1h"
+var nowPattern = regexp.MustCompile(`^now(?:([+-])(\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h))+)?$`)
+
+// asTime interprets a value as a timestamp: RFC3339 strings and "now[+-duration]"
+func asTime(value interface{}) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if nowPattern.MatchString(s) {
+		now := time.Now()
+		if len(s) > 3 {
+			d, err := time.ParseDuration(s[4:])
+			if err != nil {
+				return time.Time{}, false
+			}
+			if s[3] == '-' {
+				d = -d
+			}
+			now = now.Add(d)
+		}
+		return now, true
+	}
+	return time.Time{}, false
+}
+
+// asNumber interprets a value as a number
+func asNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// compareValues compares two values with type awareness: bools, numbers and
+// RFC3339 timestamps compare by value, everything else as strings. It returns
+// -1, 0 or 1.
+func compareValues(a, b interface{}) (int, error) {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return 0, nil
+		}
+		if a == nil {
+			return -1, nil
+		}
+		return 1, nil
+	}
+	
+	if ab, ok := a.(bool); ok {
+		bb, ok := b.(bool)
+		if !ok {
+			return 0, fmt.Errorf("cannot compare bool with %v", b)
+		}
+		switch {
+		case ab == bb:
+			return 0, nil
+		case !ab:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	}
+	
+	_, aIsString := a.(string)
+	_, bIsString := b.(string)
+	if !aIsString || !bIsString {
+		if an, ok := asNumber(a); ok {
+			if bn, ok := asNumber(b); ok {
+				return compareOrdered(an, bn), nil
+			}
+		}
+	}
+	
+	if at, ok := asTime(a); ok {
+		if bt, ok := asTime(b); ok {
+			return compareOrdered(at.UnixNano(), bt.UnixNano()), nil
+		}
+	}
+	
+	return strings.Compare(jsonPathString(a), jsonPathString(b)), nil
+}
+
+// compareOrdered compares two ordered values
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// expression is a node of a parsed --where expression
+type expression interface {
+	eval(item interface{}) (interface{}, error)
+}
+
+// fieldExpr looks up a JSON field of the current item
+type fieldExpr struct{ path string }
+
+// literalExpr is a constant
+type literalExpr struct{ value interface{} }
+
+// notExpr negates its operand
+type notExpr struct{ operand expression }
+
+// binaryExpr applies a logical or comparison operator
+type binaryExpr struct {
+	op          string
+	left, right expression
+}
+
+func (e fieldExpr) eval(item interface{}) (interface{}, error) {
+	return lookupField(item, e.path), nil
+}
+
+func (e literalExpr) eval(interface{}) (interface{}, error) {
+	return e.value, nil
+}
+
+func (e notExpr) eval(item interface{}) (interface{}, error) {
+	value, err := e.operand.eval(item)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(value), nil
+}
+
+func (e binaryExpr) eval(item interface{}) (interface{}, error) {
+	left, err := e.left.eval(item)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Logical operators short-circuit
+	switch e.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := e.right.eval(item)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := e.right.eval(item)
+		return truthy(right), err
+	}
+	
+	right, err := e.right.eval(item)
+	if err != nil {
+		return nil, err
+	}
+	
+	switch e.op {
+	case "=~", "!~":
+		re, err := regexp.Compile(jsonPathString(right))
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(jsonPathString(left)) == (e.op == "=~"), nil
+	case "==", "!=":
+		// Missing fields only equal null
+		if left == nil || right == nil {
+			return (left == nil && right == nil) == (e.op == "=="), nil
+		}
+		c, err := compareValues(left, right)
+		if err != nil {
+			return e.op == "!=", nil
+		}
+		return (c == 0) == (e.op == "=="), nil
+	}
+	
+	if left == nil || right == nil {
+		return false, nil
+	}
+	c, err := compareValues(left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", e.op)
+}
+
+// token is a lexical token of a --where expression
+type token struct {
+	kind  string // "ident", "string", "number", "op", "(", ")" or "eof"
+	value string
+}
+
+// tokenize splits a --where expression into tokens
+func tokenize(input string) ([]token, error) {
+	tokens := make([]token, 0)
+	operators := []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!"}
+	
+	for i := 0; i < len(input); {
+		c := rune(input[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{kind: string(c)})
+			i++
+			
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(input) && input[end] != input[i] {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			value := strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`).Replace(input[i+1 : end])
+			tokens = append(tokens, token{kind: "string", value: value})
+			i = end + 1
+			
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(input) && unicode.IsDigit(rune(input[i+1]))):
+			end := i + 1
+			for end < len(input) && (unicode.IsDigit(rune(input[end])) || input[end] == '.' || input[end] == 'e' || input[end] == 'E') {
+				end++
+			}
+			tokens = append(tokens, token{kind: "number", value: input[i:end]})
+			i = end
+			
+		case unicode.IsLetter(c) || c == '_' || c == '.':
+			end := i + 1
+			for end < len(input) {
+				r := rune(input[end])
+				if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-') {
+					break
+				}
+				end++
+			}
+			tokens = append(tokens, token{kind: "ident", value: input[i:end]})
+			i = end
+			
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(input[i:], op) {
+					tokens = append(tokens, token{kind: "op", value: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+			}
+		}
+	}
+	
+	return append(tokens, token{kind: "eof"}), nil
+}
+
+// exprParser is a recursive descent parser for --where expressions:
+//
+//	or   = and { "||" and }
+//	and  = not { "&&" not }
+//	not  = "!" not | cmp
+//	cmp  = atom [ ("=="|"!="|"<"|"<="|">"|">="|"=~"|"!~") atom ]
+//	atom = field | string | number | true | false | null | "(" or ")"
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseExpression parses a --where expression such as 'up == false && is_muted == false'
+func parseExpression(input string) (expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where expression: %v", err)
+	}
+	
+	p := &exprParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where expression: %v", err)
+	}
+	if p.peek().kind != "eof" {
+		return nil, fmt.Errorf("invalid --where expression: unexpected %q", p.peek().value)
+	}
+	return expr, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != "eof" {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == "op" && p.peek().value == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == "op" && p.peek().value == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (expression, error) {
+	if p.peek().kind == "op" && p.peek().value == "!" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (expression, error) {
+	left, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	
+	if t := p.peek(); t.kind == "op" {
+		switch t.value {
+		case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+			p.next()
+			right, err := p.parseAtom()
+			if err != nil {
+				return nil, err
+			}
+			return binaryExpr{op: t.value, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAtom() (expression, error) {
+	t := p.next()
+	switch t.kind {
+	case "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return expr, nil
+	case "string":
+		return literalExpr{value: t.value}, nil
+	case "number":
+		return literalExpr{value: json.Number(t.value)}, nil
+	case "ident":
+		switch t.value {
+		case "true":
+			return literalExpr{value: true}, nil
+		case "false":
+			return literalExpr{value: false}, nil
+		case "null", "nil":
+			return literalExpr{value: nil}, nil
+		}
+		return fieldExpr{path: t.value}, nil
+	case "eof":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.value)
+	}
+}
@@ -0,0 +1,94 @@
+package console
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type filterRow struct {
+	Name           string    `json:"name"`
+	Up             bool      `json:"up"`
+	IsMuted        bool      `json:"is_muted"`
+	Load           float64   `json:"load"`
+	LastReportedAt time.Time `json:"last_reported_at"`
+}
+
+func TestFormatter_ApplyListOptions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []filterRow{
+		{Name: "web-01", Up: true, Load: 10, LastReportedAt: base.Add(2 * time.Hour)},
+		{Name: "web-02", Up: false, Load: 2.5, LastReportedAt: base},
+		{Name: "db-01", Up: false, IsMuted: true, Load: 9, LastReportedAt: base.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		where   string
+		sortBy  string
+		reverse bool
+		want    string
+		wantErr bool
+	}{
+		{name: "no options", want: "web-01,web-02,db-01"},
+		{name: "bool filter", where: "up == false && is_muted == false", want: "web-02"},
+		{name: "negation and or", where: "!up || name == 'web-01'", want: "web-01,web-02,db-01"},
+		{name: "numeric compare", where: "load >= 9", want: "web-01,db-01"},
+		{name: "timestamp compare", where: "last_reported_at < '2024-01-01T01:30:00Z'", want: "web-02,db-01"},
+		{name: "regex match", where: "name =~ '^web-'", want: "web-01,web-02"},
+		{name: "sort numeric not lexical", sortBy: "load", want: "web-02,db-01,web-01"},
+		{name: "sort by timestamp reversed", sortBy: "last_reported_at", reverse: true, want: "web-01,db-01,web-02"},
+		{name: "multiple keys", sortBy: "up,name", want: "db-01,web-02,web-01"},
+		{name: "filter and sort", where: "!up", sortBy: "name", want: "db-01,web-02"},
+		{name: "syntax error", where: "up ==", wantErr: true},
+		{name: "unbalanced parens", where: "(up", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Formatter{Where: tt.where, SortBy: tt.sortBy, Reverse: tt.reverse}
+			result, err := f.ApplyListOptions(rows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyListOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var names []string
+			for _, row := range result.([]filterRow) {
+				names = append(names, row.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("ApplyListOptions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatter_WithoutListOptions(t *testing.T) {
+	rows := []filterRow{{Name: "a", Up: true}, {Name: "b"}, {Name: "c", Up: true}}
+	f := &Formatter{Where: "up", Reverse: true}
+	
+	selected, err := f.ApplyListOptions(rows)
+	if err != nil {
+		t.Fatalf("ApplyListOptions() error = %v", err)
+	}
+	
+	// Formatting the selection again must not filter or reverse it twice
+	plain := f.WithoutListOptions()
+	again, err := plain.ApplyListOptions(selected)
+	if err != nil {
+		t.Fatalf("ApplyListOptions() error = %v", err)
+	}
+	var names []string
+	for _, row := range again.([]filterRow) {
+		names = append(names, row.Name)
+	}
+	if got := strings.Join(names, ","); got != "c,a" {
+		t.Errorf("WithoutListOptions() selection = %s, want c,a", got)
+	}
+	if f.Where != "up" || !f.Reverse {
+		t.Errorf("WithoutListOptions() modified the original formatter")
+	}
+}
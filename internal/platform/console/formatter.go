@@ -7,8 +7,9 @@ import (
 	"os"
 	"reflect"
 	"strings"
-	"text/tabwriter"
+	"time"
 
+	"github.com/padawandba/datadog-cli/internal/platform/config"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,8 +23,89 @@ const (
 	JSONFormat OutputFormat = "json"
 	// YAMLFormat outputs as YAML
 	YAMLFormat OutputFormat = "yaml"
+	// CSVFormat outputs as comma-separated values with a header row
+	CSVFormat OutputFormat = "csv"
+	// TSVFormat outputs as tab-separated values with a header row
+	TSVFormat OutputFormat = "tsv"
+	// NDJSONFormat outputs one JSON object per line
+	NDJSONFormat OutputFormat = "ndjson"
+	// GoTemplateFormat outputs the result of a Go template (-o go-template='...')
+	GoTemplateFormat OutputFormat = "go-template"
+	// JSONPathFormat outputs the result of a JSONPath template (-o jsonpath='...')
+	JSONPathFormat OutputFormat = "jsonpath"
+	// WideFormat outputs as a table with every column
+	WideFormat OutputFormat = "wide"
+	// CustomColumnsFormat outputs as a table with user-defined columns
+	// (-o custom-columns=NAME:.name,MUTED:.is_muted)
+	CustomColumnsFormat OutputFormat = "custom-columns"
+	// MarkdownFormat outputs as a GitHub-flavored Markdown table
+	MarkdownFormat OutputFormat = "markdown"
+	// HTMLFormat outputs as a self-contained HTML document with a table
+	HTMLFormat OutputFormat = "html"
 )
 
+// outputFormats lists every supported output format
+var outputFormats = []OutputFormat{
+	TableFormat,
+	JSONFormat,
+	YAMLFormat,
+	CSVFormat,
+	TSVFormat,
+	NDJSONFormat,
+	GoTemplateFormat,
+	JSONPathFormat,
+	WideFormat,
+	CustomColumnsFormat,
+	MarkdownFormat,
+	HTMLFormat,
+}
+
+// ParseOutputFormat converts an output spec to an OutputFormat. A spec is a
+// format name, optionally followed by "=" and an argument for the template
+// formats (e.g., "jsonpath={.[*].name}"), which is parsed so that a malformed
+// template is rejected. An empty spec selects the table format.
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	outputFormat, _, err := parseOutputSpec(format)
+	return outputFormat, err
+}
+
+// parseOutputSpec splits an output spec into its format and argument
+func parseOutputSpec(spec string) (OutputFormat, string, error) {
+	if spec == "" {
+		return TableFormat, "", nil
+	}
+	
+	nameStr, arg, hasArg := strings.Cut(spec, "=")
+	name := OutputFormat(strings.ToLower(nameStr))
+	switch name {
+	case "template":
+		name = GoTemplateFormat
+	case "md":
+		name = MarkdownFormat
+	}
+	
+	for _, f := range outputFormats {
+		if f != name {
+			continue
+		}
+		if hasArg && !takesArgument(f) {
+			return "", "", fmt.Errorf("output format %q does not take an argument", nameStr)
+		}
+		if arg != "" {
+			if err := checkTemplate(f, arg); err != nil {
+				return "", "", err
+			}
+		}
+		return f, arg, nil
+	}
+	
+	names := make([]string, len(outputFormats))
+	for i, f := range outputFormats {
+		names[i] = string(f)
+	}
+	return "", "", fmt.Errorf("unknown output format %q (supported: %s)", nameStr, strings.Join(names, ", "))
+}
+
 // TableOptions configures the appearance of table output
 type TableOptions struct {
 	// MinWidth is the minimum cell width including any padding
@@ -34,7 +116,7 @@ type TableOptions struct {
 	Padding int
 	// PadChar is the padding character
 	PadChar byte
-	// Flags controls formatting behavior
+	// Flags controls formatting behavior with text/tabwriter flags
 	Flags uint
 	// Header determines if headers should be displayed
 	Header bool
@@ -55,28 +137,140 @@ func DefaultTableOptions() TableOptions {
 	}
 }
 
+// MachineReadable reports whether the format is meant to be parsed by other
+// programs, so commands must not mix progress text into it
+func (f OutputFormat) MachineReadable() bool {
+	switch f {
+	case TableFormat, WideFormat, CustomColumnsFormat:
+		return false
+	}
+	return true
+}
+
+// checkTemplate parses the argument of a template format, so a malformed
+// template is reported before any API call is made
+func checkTemplate(format OutputFormat, arg string) error {
+	var err error
+	switch format {
+	case GoTemplateFormat:
+		_, err = ParseGoTemplate(arg)
+	case JSONPathFormat:
+		_, err = ParseJSONPath(arg)
+	case CustomColumnsFormat:
+		_, err = parseCustomColumns(arg)
+	}
+	return err
+}
+
+// takesArgument reports whether an output format is configured with "=ARG"
+func takesArgument(format OutputFormat) bool {
+	return format == GoTemplateFormat || format == JSONPathFormat || format == CustomColumnsFormat
+}
+
 // Formatter formats data for CLI output
 type Formatter struct {
 	OutFormat    OutputFormat
 	Writer       io.Writer
 	TableOptions TableOptions
+	// Template is the go-template, JSONPath template or custom-columns spec
+	// for the formats that take an argument
+	Template string
+	// Columns selects and orders the columns of table and delimited output by json name
+	Columns []string
+	// Wide shows every column in table output instead of a type's default columns
+	Wide bool
+	// SortBy orders slice output by comma-separated json field names
+	SortBy string
+	// Reverse reverses the order of slice output
+	Reverse bool
+	// Where filters slice output with an expression over json field names
+	Where string
+	// Color colors state columns in table output written to a terminal
+	Color bool
+	// Envelope wraps JSON and YAML output in an Envelope with metadata
+	Envelope bool
+	// Site and Profile are reported in envelope metadata
+	Site    string
+	Profile string
+	
+	// secondary ignores selected columns a table lacks; see Secondary
+	secondary bool
+	// termWidth is the width of the terminal being written to, or 0
+	termWidth int
+	// diff tracks table rows between redraws in watch mode
+	diff *rowDiff
+	// page and total are reported in envelope metadata when set
+	page  *int64
+	total *int64
 }
 
-// NewFormatter creates a new formatter with the specified format
-func NewFormatter(format string) *Formatter {
-	outputFormat := TableFormat
-	switch strings.ToLower(format) {
-	case "json":
-		outputFormat = JSONFormat
-	case "yaml":
-		outputFormat = YAMLFormat
+// NewFormatter creates a new formatter with the specified output spec
+// (e.g., "table", "csv" or "go-template={{range .}}{{.name}}{{end}}")
+func NewFormatter(format string) (*Formatter, error) {
+	outputFormat, arg, err := parseOutputSpec(format)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Formatter{
+	f := &Formatter{
 		OutFormat:    outputFormat,
 		Writer:       os.Stdout,
 		TableOptions: DefaultTableOptions(),
+		Template:     arg,
+		Color:        !colorDisabledByEnv(),
 	}
+	
+	// Wide output is a table without the default column narrowing
+	if outputFormat == WideFormat {
+		f.OutFormat = TableFormat
+		f.Wide = true
+	}
+	
+	return f, nil
+}
+
+// NewFormatterFromConfig creates a new formatter using the output settings in cfg
+func NewFormatterFromConfig(cfg *config.Config) (*Formatter, error) {
+	f, err := NewFormatter(cfg.Output)
+	if err != nil {
+		return nil, err
+	}
+	f.Columns = cfg.Columns
+	f.SortBy = cfg.SortBy
+	f.Reverse = cfg.Reverse
+	f.Where = cfg.Where
+	if cfg.NoColor {
+		f.Color = false
+	}
+	f.Envelope = cfg.JSONEnvelope
+	f.Site = cfg.Site
+	f.Profile = cfg.Profile
+	
+	// Reject malformed filters before any API calls are made
+	if f.Where != "" {
+		if _, err := parseExpression(f.Where); err != nil {
+			return nil, err
+		}
+	}
+	
+	// A template file implies go-template output
+	if cfg.TemplateFile != "" {
+		text, err := ReadTemplateFile(cfg.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTemplate(GoTemplateFormat, text); err != nil {
+			return nil, err
+		}
+		f.OutFormat = GoTemplateFormat
+		f.Template = text
+	}
+	
+	if takesArgument(f.OutFormat) && f.Template == "" {
+		return nil, fmt.Errorf("output format %q requires a template (e.g., -o %s=...)", f.OutFormat, f.OutFormat)
+	}
+	
+	return f, nil
 }
 
 // WithTableOptions sets custom table options and returns the formatter
@@ -106,11 +300,39 @@ func (f *Formatter) Format(data interface{}) error {
 		f.Writer = os.Stdout
 	}
 
+	// Tables written to a terminal are fitted to its width
+	f.termWidth = TerminalWidth(f.Writer)
+
+	data, err := f.ApplyListOptions(data)
+	if err != nil {
+		return err
+	}
+	
+	if f.Envelope && (f.OutFormat == JSONFormat || f.OutFormat == YAMLFormat) {
+		data = f.envelope(data)
+	}
+
 	switch f.OutFormat {
 	case JSONFormat:
 		return f.formatJSON(data)
 	case YAMLFormat:
 		return f.formatYAML(data)
+	case CSVFormat:
+		return f.formatDelimited(data, ',')
+	case TSVFormat:
+		return f.formatDelimited(data, '\t')
+	case NDJSONFormat:
+		return f.formatNDJSON(data)
+	case GoTemplateFormat:
+		return f.formatGoTemplate(data)
+	case JSONPathFormat:
+		return f.formatJSONPath(data)
+	case CustomColumnsFormat:
+		return f.formatCustomColumns(data)
+	case MarkdownFormat:
+		return f.formatMarkdown(data)
+	case HTMLFormat:
+		return f.formatHTML(data)
 	default:
 		return f.formatTable(data)
 	}
@@ -173,24 +395,20 @@ func (f *Formatter) formatTable(data interface{}) error {
 	}
 }
 
-// createTabWriter creates a tabwriter with the formatter's options
-func (f *Formatter) createTabWriter() *tabwriter.Writer {
-	return tabwriter.NewWriter(
-		f.Writer,
-		f.TableOptions.MinWidth,
-		f.TableOptions.TabWidth,
-		f.TableOptions.Padding,
-		f.TableOptions.PadChar,
-		f.TableOptions.Flags,
-	)
+// cellValue prepares a value for a table cell: control characters are
+// escaped, and the value is truncated if it exceeds the max column width.
+// Tables written to a terminal are instead fitted to the terminal width.
+func (f *Formatter) cellValue(value string) string {
+	value = escapeCell(value)
+	if f.termWidth > 0 {
+		return value
+	}
+	return TruncateDisplay(value, f.TableOptions.MaxColumnWidth)
 }
 
-// truncateValue truncates a string value if it exceeds the max column width
-func (f *Formatter) truncateValue(value string) string {
-	if f.TableOptions.MaxColumnWidth > 0 && len(value) > f.TableOptions.MaxColumnWidth {
-		return value[:f.TableOptions.MaxColumnWidth-3] + "..."
-	}
-	return value
+// useColor reports whether table cells should be colored
+func (f *Formatter) useColor() bool {
+	return f.Color && IsTerminal(f.Writer)
 }
 
 // safeString safely converts a value to a string, handling potential panics
@@ -213,6 +431,21 @@ func (f *Formatter) safeString(v reflect.Value) string {
 		if v.Len() == 0 {
 			return "<empty>"
 		}
+		if v.Kind() == reflect.Map {
+			return flattenValue(v)
+		}
+		// Render lists as "a, b, c" rather than Go's "[a b c]"
+		parts := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts[i] = flattenValue(v.Index(i))
+		}
+		return strings.Join(parts, ", ")
+	
+	case reflect.Struct:
+		// Times match the delimited formats
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339)
+		}
 	}
 	
 	// Default string conversion
@@ -255,12 +488,45 @@ func (f *Formatter) formatSliceAsTable(v reflect.Value) error {
 	for i := 0; i < v.Len(); i++ {
 		elem := v.Index(i)
 		value := f.safeString(elem)
-		fmt.Fprintf(w, "%s\n", f.truncateValue(value))
+		fmt.Fprintf(w, "%s\n", f.cellValue(value))
 	}
 	
 	return nil
 }
 
+// structFields returns the display names and field indices of a struct type's
+// exported fields, using the json tag name when present and skipping json:"-"
+func structFields(structType reflect.Type) ([]string, []int) {
+	headers := make([]string, 0)
+	fieldIndices := make([]int, 0)
+	
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		
+		// Skip unexported fields
+		if !field.IsExported() {
+			continue
+		}
+		
+		// Check for json tag to use as header
+		header := field.Name
+		jsonTag := field.Tag.Get("json")
+		if jsonTag != "" {
+			parts := strings.Split(jsonTag, ",")
+			if parts[0] == "-" { // Skip fields with json:"-"
+				continue
+			}
+			if parts[0] != "" {
+				header = parts[0]
+			}
+		}
+		headers = append(headers, header)
+		fieldIndices = append(fieldIndices, i)
+	}
+	
+	return headers, fieldIndices
+}
+
 // formatStructSliceAsTable formats a slice of structs as a table with headers
 func (f *Formatter) formatStructSliceAsTable(v reflect.Value) error {
 	// Handle empty slices
@@ -281,34 +547,10 @@ func (f *Formatter) formatStructSliceAsTable(v reflect.Value) error {
 	
 	structType := firstElem.Type()
 	
-	// Extract field names for headers, considering JSON tags
-	headers := make([]string, 0)
-	fieldIndices := make([]int, 0)
-	
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
-		
-		// Check for json tag to use as header
-		header := field.Name
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "-" { // Skip fields with json:"-"
-				if parts[0] != "" {
-					header = parts[0]
-				}
-				headers = append(headers, header)
-				fieldIndices = append(fieldIndices, i)
-			}
-		} else {
-			headers = append(headers, header)
-			fieldIndices = append(fieldIndices, i)
-		}
+	// Extract field names for headers, considering JSON tags and column selection
+	headers, fieldIndices, err := f.selectColumns(structType, true)
+	if err != nil {
+		return err
 	}
 	
 	// Handle case where no fields were found
@@ -339,7 +581,7 @@ func (f *Formatter) formatStructSliceAsTable(v reflect.Value) error {
 			
 			field := row.Field(fieldIdx)
 			value := f.safeString(field)
-			values[j] = f.truncateValue(value)
+			values[j] = f.cellValue(value)
 		}
 		
 		fmt.Fprintln(w, strings.Join(values, "\t"))
@@ -374,34 +616,10 @@ func (f *Formatter) formatStructPtrSliceAsTable(v reflect.Value) error {
 	
 	structType := elemValue.Type()
 	
-	// Extract field names for headers, considering JSON tags
-	headers := make([]string, 0)
-	fieldIndices := make([]int, 0)
-	
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
-		
-		// Check for json tag to use as header
-		header := field.Name
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "-" { // Skip fields with json:"-"
-				if parts[0] != "" {
-					header = parts[0]
-				}
-				headers = append(headers, header)
-				fieldIndices = append(fieldIndices, i)
-			}
-		} else {
-			headers = append(headers, header)
-			fieldIndices = append(fieldIndices, i)
-		}
+	// Extract field names for headers, considering JSON tags and column selection
+	headers, fieldIndices, err := f.selectColumns(structType, true)
+	if err != nil {
+		return err
 	}
 	
 	// Handle case where no fields were found
@@ -443,7 +661,7 @@ func (f *Formatter) formatStructPtrSliceAsTable(v reflect.Value) error {
 			
 			field := row.Field(fieldIdx)
 			value := f.safeString(field)
-			values[j] = f.truncateValue(value)
+			values[j] = f.cellValue(value)
 		}
 		
 		fmt.Fprintln(w, strings.Join(values, "\t"))
@@ -467,31 +685,17 @@ func (f *Formatter) formatStructAsTable(v reflect.Value) error {
 		fmt.Fprintln(w, "FIELD\tVALUE")
 	}
 	
-	t := v.Type()
+	// Detail views show every field unless columns were selected explicitly
+	fieldNames, fieldIndices, err := f.selectColumns(v.Type(), false)
+	if err != nil {
+		return err
+	}
 	fieldCount := 0
 	
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		if !field.IsExported() {
-			continue
-		}
-		
-		// Check for json tag to use as field name
-		fieldName := field.Name
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] == "-" {
-				continue // Skip fields with json:"-"
-			}
-			if parts[0] != "" {
-				fieldName = parts[0]
-			}
-		}
-		
-		fieldValue := v.Field(i)
+	for j, fieldIdx := range fieldIndices {
+		fieldValue := v.Field(fieldIdx)
 		value := f.safeString(fieldValue)
-		fmt.Fprintf(w, "%s\t%s\n", fieldName, f.truncateValue(value))
+		fmt.Fprintf(w, "%s\t%s\n", fieldNames[j], f.cellValue(value))
 		fieldCount++
 	}
 	
@@ -535,7 +739,7 @@ func (f *Formatter) formatMapAsTable(v reflect.Value) error {
 	for iter.Next() {
 		key := f.safeString(iter.Key())
 		value := f.safeString(iter.Value())
-		fmt.Fprintf(w, "%s\t%s\n", f.truncateValue(key), f.truncateValue(value))
+		fmt.Fprintf(w, "%s\t%s\n", f.cellValue(key), f.cellValue(value))
 	}
 	
 	return nil
@@ -0,0 +1,405 @@
+package console
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonPathNode is a parsed piece of a JSONPath template
+type jsonPathNode struct {
+	// text is written as-is when path is nil and children are empty
+	text string
+	// path is the expression to evaluate, for expressions and range blocks
+	path []jsonPathStep
+	// isRange marks a {range ...}{end} block whose children are executed per result
+	isRange  bool
+	children []jsonPathNode
+}
+
+// jsonPathStep is a single step of a JSONPath expression
+type jsonPathStep struct {
+	// kind is one of "root", "field", "recursive", "wildcard", "index" or "slice"
+	kind  string
+	name  string
+	index int
+	start *int
+	end   *int
+}
+
+// JSONPath is a compiled kubectl-style JSONPath template, such as
+// '{.[*].host_name}' or '{range .[*]}{.name}{"\t"}{.up}{"\n"}{end}'
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+// ParseJSONPath compiles a JSONPath template. Text outside braces is copied to
+// the output. Inside braces, paths support .field, ['field'], [n], [*], .*,
+// [start:end] and ..field, plus "quoted" literals and range/end blocks.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	// Allow a bare expression without braces, e.g. '.[*].name'
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+	
+	nodes, _, err := parseJSONPathNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseJSONPathNodes parses nodes until the input is exhausted or, inside a
+// range block, until the matching {end}. It returns the input following {end}.
+func parseJSONPathNodes(input string, inRange bool) ([]jsonPathNode, string, error) {
+	nodes := make([]jsonPathNode, 0)
+	
+	for input != "" {
+		open := strings.Index(input, "{")
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: input})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: input[:open]})
+		}
+		
+		closeIdx := findClosing(input[open:], '}')
+		if closeIdx < 0 {
+			return nil, "", fmt.Errorf("jsonpath: unclosed brace in %q", input[open:])
+		}
+		expr := strings.TrimSpace(input[open+1 : open+closeIdx])
+		input = input[open+closeIdx+1:]
+		
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("jsonpath: {end} without {range}")
+			}
+			return nodes, input, nil
+			
+		case strings.HasPrefix(expr, "range "):
+			path, err := parseJSONPathExpr(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			children, rest, err := parseJSONPathNodes(input, true)
+			if err != nil {
+				return nil, "", err
+			}
+			input = rest
+			nodes = append(nodes, jsonPathNode{path: path, isRange: true, children: children})
+			
+		case strings.HasPrefix(expr, `"`):
+			text, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, "", fmt.Errorf("jsonpath: invalid literal %s: %v", expr, err)
+			}
+			nodes = append(nodes, jsonPathNode{text: text})
+			
+		default:
+			path, err := parseJSONPathExpr(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path})
+		}
+	}
+	
+	if inRange {
+		return nil, "", fmt.Errorf("jsonpath: {range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// findClosing returns the index of the first closing character after the
+// opening one at s[0], skipping any inside quoted literals. A literal ends
+// only at the quote character that opened it, so "it's" is one literal.
+func findClosing(s string, closing byte) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == closing:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseJSONPathExpr parses a path expression such as '.[*].host_name'
+func parseJSONPathExpr(expr string) ([]jsonPathStep, error) {
+	steps := make([]jsonPathStep, 0)
+	s := expr
+	
+	if strings.HasPrefix(s, "$") {
+		steps = append(steps, jsonPathStep{kind: "root"})
+		s = s[1:]
+	}
+	
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			name, rest := readJSONPathName(s[2:])
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: expected field name after '..' in %q", expr)
+			}
+			steps = append(steps, jsonPathStep{kind: "recursive", name: name})
+			s = rest
+			
+		case strings.HasPrefix(s, ".*"):
+			steps = append(steps, jsonPathStep{kind: "wildcard"})
+			s = s[2:]
+			
+		case strings.HasPrefix(s, "."):
+			name, rest := readJSONPathName(s[1:])
+			if name != "" {
+				steps = append(steps, jsonPathStep{kind: "field", name: name})
+			}
+			s = rest
+			
+		case strings.HasPrefix(s, "["):
+			end := findClosing(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed bracket in %q", expr)
+			}
+			step, err := parseJSONPathBracket(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: %v in %q", err, expr)
+			}
+			steps = append(steps, step)
+			s = s[end+1:]
+			
+		default:
+			// Allow a leading field name without a dot, e.g. 'name'
+			name, rest := readJSONPathName(s)
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: unexpected %q in %q", s, expr)
+			}
+			steps = append(steps, jsonPathStep{kind: "field", name: name})
+			s = rest
+		}
+	}
+	
+	return steps, nil
+}
+
+// readJSONPathName reads a field name up to the next '.' or '['
+func readJSONPathName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// parseJSONPathBracket parses the contents of a [...] step
+func parseJSONPathBracket(content string) (jsonPathStep, error) {
+	switch {
+	case content == "*":
+		return jsonPathStep{kind: "wildcard"}, nil
+		
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		quote := content[:1]
+		if len(content) < 2 || !strings.HasSuffix(content, quote) {
+			return jsonPathStep{}, fmt.Errorf("unterminated field name %s", content)
+		}
+		return jsonPathStep{kind: "field", name: content[1 : len(content)-1]}, nil
+		
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		step := jsonPathStep{kind: "slice"}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return step, fmt.Errorf("invalid slice bound %q", part)
+			}
+			if i == 0 {
+				step.start = &n
+			} else {
+				step.end = &n
+			}
+		}
+		return step, nil
+		
+	default:
+		n, err := strconv.Atoi(content)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("unsupported bracket expression [%s]", content)
+		}
+		return jsonPathStep{kind: "index", index: n}, nil
+	}
+}
+
+// Execute evaluates the template against data, which must be a generic JSON
+// value as produced by encoding/json, and writes the result to w
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	return executeJSONPathNodes(w, j.nodes, data, data)
+}
+
+// executeJSONPathNodes writes nodes evaluated against the current value
+func executeJSONPathNodes(w io.Writer, nodes []jsonPathNode, root, current interface{}) error {
+	for _, node := range nodes {
+		if node.path == nil {
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+			continue
+		}
+		
+		results := evalJSONPath(node.path, root, current)
+		
+		if node.isRange {
+			// Range over the elements of a single list result, or over every result
+			if len(results) == 1 {
+				if list, ok := results[0].([]interface{}); ok {
+					results = list
+				}
+			}
+			for _, result := range results {
+				if err := executeJSONPathNodes(w, node.children, root, result); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		
+		parts := make([]string, 0, len(results))
+		for _, result := range results {
+			parts = append(parts, jsonPathString(result))
+		}
+		if _, err := io.WriteString(w, strings.Join(parts, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalJSONPath evaluates path steps against the current value
+func evalJSONPath(steps []jsonPathStep, root, current interface{}) []interface{} {
+	values := []interface{}{current}
+	
+	for _, step := range steps {
+		next := make([]interface{}, 0)
+		for _, value := range values {
+			switch step.kind {
+			case "root":
+				next = append(next, root)
+				
+			case "field":
+				if m, ok := value.(map[string]interface{}); ok {
+					if v, ok := m[step.name]; ok {
+						next = append(next, v)
+					}
+				}
+				
+			case "recursive":
+				next = append(next, findRecursive(value, step.name)...)
+				
+			case "wildcard":
+				switch v := value.(type) {
+				case []interface{}:
+					next = append(next, v...)
+				case map[string]interface{}:
+					for _, key := range sortedKeys(v) {
+						next = append(next, v[key])
+					}
+				}
+				
+			case "index":
+				if list, ok := value.([]interface{}); ok {
+					i := step.index
+					if i < 0 {
+						i += len(list)
+					}
+					if i >= 0 && i < len(list) {
+						next = append(next, list[i])
+					}
+				}
+				
+			case "slice":
+				if list, ok := value.([]interface{}); ok {
+					start, end := 0, len(list)
+					if step.start != nil {
+						start = clampIndex(*step.start, len(list))
+					}
+					if step.end != nil {
+						end = clampIndex(*step.end, len(list))
+					}
+					if start < end {
+						next = append(next, list[start:end]...)
+					}
+				}
+			}
+		}
+		values = next
+	}
+	
+	return values
+}
+
+// findRecursive returns every value stored under name at any depth
+func findRecursive(value interface{}, name string) []interface{} {
+	results := make([]interface{}, 0)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if key == name {
+				results = append(results, v[key])
+			}
+			results = append(results, findRecursive(v[key], name)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			results = append(results, findRecursive(item, name)...)
+		}
+	}
+	return results
+}
+
+// clampIndex resolves a possibly negative slice bound against a length
+func clampIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+// jsonPathString renders a JSONPath result: scalars as plain text, objects and lists as JSON
+func jsonPathString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
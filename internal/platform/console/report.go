@@ -0,0 +1,215 @@
+package console
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+)
+
+// markdownEscaper escapes characters that would break a Markdown table cell
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+	"\r", "<br>",
+)
+
+// formatMarkdown formats data as a GitHub-flavored Markdown table
+func (f *Formatter) formatMarkdown(data interface{}) error {
+	var b strings.Builder
+	if err := f.writeMarkdownTable(&b, data); err != nil {
+		return err
+	}
+	_, err := fmt.Fprint(f.Writer, b.String())
+	return err
+}
+
+// writeMarkdownTable writes data as a Markdown table
+func (f *Formatter) writeMarkdownTable(b *strings.Builder, data interface{}) error {
+	headers, rows, err := f.records(data, true)
+	if err != nil {
+		return err
+	}
+	
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+		}
+		b.WriteString("\n")
+	}
+	
+	// GFM tables require a header row, so it is always written
+	writeRow(headers)
+	b.WriteString("|" + strings.Repeat(" --- |", len(headers)) + "\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+	return nil
+}
+
+// htmlStyle is the inline stylesheet for HTML reports
+const htmlStyle = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+tr:nth-child(even) td { background: #fafbfc; }`
+
+// formatHTML formats data as a self-contained HTML document with one table
+func (f *Formatter) formatHTML(data interface{}) error {
+	var b strings.Builder
+	writeHTMLHeader(&b)
+	if err := f.writeHTMLTable(&b, data); err != nil {
+		return err
+	}
+	writeHTMLFooter(&b)
+	
+	_, err := fmt.Fprint(f.Writer, b.String())
+	return err
+}
+
+// writeHTMLHeader starts an HTML report document
+func writeHTMLHeader(b *strings.Builder) {
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>Datadog CLI report</title>\n")
+	b.WriteString("<style>\n" + htmlStyle + "\n</style>\n")
+	b.WriteString("</head>\n<body>\n")
+}
+
+// writeHTMLFooter ends an HTML report document
+func writeHTMLFooter(b *strings.Builder) {
+	b.WriteString("</body>\n</html>\n")
+}
+
+// htmlCell escapes a value for an HTML table cell
+func htmlCell(value string) string {
+	return strings.ReplaceAll(html.EscapeString(value), "\n", "<br>")
+}
+
+// writeHTMLTable writes data as an HTML table
+func (f *Formatter) writeHTMLTable(b *strings.Builder, data interface{}) error {
+	headers, rows, err := f.records(data, true)
+	if err != nil {
+		return err
+	}
+	
+	b.WriteString("<table>\n")
+	if f.TableOptions.Header {
+		b.WriteString("<thead>\n<tr>")
+		for _, header := range headers {
+			b.WriteString("<th>" + htmlCell(header) + "</th>")
+		}
+		b.WriteString("</tr>\n</thead>\n")
+	}
+	
+	b.WriteString("<tbody>\n")
+	for _, row := range rows {
+		b.WriteString("<tr>")
+		for _, value := range row {
+			b.WriteString("<td>" + htmlCell(value) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return nil
+}
+
+// Section is one titled table of a report with several tables
+type Section struct {
+	Title string
+	Data  interface{}
+	// Empty is shown instead of the table when Data is an empty list
+	Empty string
+	// Secondary formats the table like Formatter.Secondary
+	Secondary bool
+}
+
+// FormatSections formats several tables as a single document: one Markdown
+// document with a heading per section, or one HTML document with a table per
+// section. Tables are written one after the other, without titles. Other
+// formats, which hold a single document of one shape, are not supported; use
+// Format with a value that holds every section instead.
+func (f *Formatter) FormatSections(sections ...Section) error {
+	var b strings.Builder
+	switch f.OutFormat {
+	case MarkdownFormat:
+	case HTMLFormat:
+		writeHTMLHeader(&b)
+	case TableFormat, CustomColumnsFormat:
+		return f.formatTableSections(sections)
+	default:
+		return fmt.Errorf("output format %q cannot show several tables", f.OutFormat)
+	}
+	
+	for i, section := range sections {
+		formatter := f
+		if section.Secondary {
+			formatter = f.Secondary()
+		}
+		data, err := formatter.ApplyListOptions(section.Data)
+		if err != nil {
+			return err
+		}
+		
+		if f.OutFormat == HTMLFormat {
+			b.WriteString("<h2>" + htmlCell(section.Title) + "</h2>\n")
+			if isEmptyList(data) && section.Empty != "" {
+				b.WriteString("<p>" + htmlCell(section.Empty) + "</p>\n")
+			} else if err := formatter.writeHTMLTable(&b, data); err != nil {
+				return err
+			}
+			continue
+		}
+		
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## " + section.Title + "\n\n")
+		if isEmptyList(data) && section.Empty != "" {
+			b.WriteString(markdownEscaper.Replace(section.Empty) + "\n")
+		} else if err := formatter.writeMarkdownTable(&b, data); err != nil {
+			return err
+		}
+	}
+	
+	if f.OutFormat == HTMLFormat {
+		writeHTMLFooter(&b)
+	}
+	_, err := fmt.Fprint(f.Writer, b.String())
+	return err
+}
+
+// formatTableSections writes the tables of sections one after the other,
+// separated by a blank line
+func (f *Formatter) formatTableSections(sections []Section) error {
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(f.Writer)
+		}
+		
+		formatter := f
+		if section.Secondary {
+			formatter = f.Secondary()
+		}
+		data, err := formatter.ApplyListOptions(section.Data)
+		if err != nil {
+			return err
+		}
+		if isEmptyList(data) && section.Empty != "" {
+			fmt.Fprintln(f.Writer, section.Empty)
+			continue
+		}
+		if err := formatter.WithoutListOptions().Format(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEmptyList reports whether data is a slice or array with no elements
+func isEmptyList(data interface{}) bool {
+	v := reflect.ValueOf(data)
+	return v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Len() == 0
+}
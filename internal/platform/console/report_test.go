@@ -0,0 +1,105 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatter_Markdown(t *testing.T) {
+	f, err := NewFormatter("markdown")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	rows := []testRow{{Name: "a|b", Tags: []string{"env:prod"}}, {Name: "line1\nline2"}}
+	if err := f.Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Format() produced %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "| --- |") {
+		t.Errorf("separator row = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], `| a\|b |`) {
+		t.Errorf("pipe not escaped: %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "| line1<br>line2 |") {
+		t.Errorf("newline not escaped: %q", lines[3])
+	}
+}
+
+func TestFormatter_HTML(t *testing.T) {
+	f, err := NewFormatter("html")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	rows := []testRow{{Name: "<script>alert(1)</script>"}}
+	if err := f.Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "<!DOCTYPE html>") {
+		t.Errorf("Format() is not a complete document:\n%s", out)
+	}
+	if strings.Contains(out, "<script>") {
+		t.Errorf("Format() did not escape cell values:\n%s", out)
+	}
+	if !strings.Contains(out, "<td>&lt;script&gt;alert(1)&lt;/script&gt;</td>") {
+		t.Errorf("Format() missing escaped cell:\n%s", out)
+	}
+}
+
+func TestFormatter_FormatSections(t *testing.T) {
+	sections := []Section{
+		{Title: "Rows", Data: []testRow{{Name: "a"}}},
+		{Title: "Nothing", Data: []testRow{}, Empty: "Nothing to show"},
+	}
+
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{output: "html", want: []string{"<h2>Rows</h2>\n<table>", "<td>a</td>", "<h2>Nothing</h2>\n<p>Nothing to show</p>"}},
+		{output: "markdown", want: []string{"## Rows\n\n| name |", "| a |", "\n## Nothing\n\nNothing to show\n"}},
+		{output: "table", want: []string{"a", "\n\nNothing to show\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			f, err := NewFormatter(tt.output)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			var buf bytes.Buffer
+			f.WithWriter(&buf)
+
+			if err := f.FormatSections(sections...); err != nil {
+				t.Fatalf("FormatSections() error = %v", err)
+			}
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("FormatSections() missing %q:\n%s", want, out)
+				}
+			}
+			if n := strings.Count(out, "<!DOCTYPE html>"); tt.output == "html" && n != 1 {
+				t.Errorf("FormatSections() wrote %d HTML documents, want 1:\n%s", n, out)
+			}
+		})
+	}
+
+	f, _ := NewFormatter("csv")
+	if err := f.FormatSections(sections...); err == nil {
+		t.Errorf("FormatSections() with csv succeeded, want an error")
+	}
+}
@@ -0,0 +1,168 @@
+package console
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDialect is the JSON Schema version of generated schemas
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaBaseURL is the location the schemas under schemas/ are published at
+const schemaBaseURL = "https://github.com/padawandba/datadog-cli/schemas/"
+
+// SchemaFileName returns the file name of the envelope schema for an item
+// type, e.g. "host-list.schema.json" for a type of kind "Host"
+func SchemaFileName(item interface{}) string {
+	return kebabCase(KindOf(reflect.TypeOf(item))) + "-list.schema.json"
+}
+
+// EnvelopeSchema generates the JSON Schema of the --json-envelope output for
+// a list of item, derived from the item's Go type and json tags
+func EnvelopeSchema(item interface{}) ([]byte, error) {
+	itemType := reflect.TypeOf(item)
+	kind := KindOf(itemType)
+	
+	g := &schemaGenerator{defs: make(map[string]interface{})}
+	itemSchema := g.schemaFor(itemType)
+	
+	metadata := g.schemaFor(reflect.TypeOf(EnvelopeMetadata{}))
+	
+	schema := map[string]interface{}{
+		"$schema":  jsonSchemaDialect,
+		"$id":      schemaBaseURL + strings.TrimPrefix(EnvelopeAPIVersion, "datadog-cli/") + "/" + SchemaFileName(item),
+		"title":    kind + "List",
+		"type":     "object",
+		"required": []string{"apiVersion", "kind", "items", "metadata"},
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"const": EnvelopeAPIVersion},
+			"kind":       map[string]interface{}{"const": kind + "List"},
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": itemSchema,
+			},
+			"metadata": metadata,
+		},
+		"$defs": g.defs,
+	}
+	
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaGenerator builds JSON Schemas from Go types, collecting named
+// structs under $defs
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+// schemaFor returns the schema of a Go type as encoded by encoding/json
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	
+	switch t.Kind() {
+	case reflect.Pointer:
+		return map[string]interface{}{
+			"anyOf": []interface{}{g.schemaFor(t.Elem()), map[string]interface{}{"type": "null"}},
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		// Nil slices encode as null
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		// interface{} and anything else accepts any value
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns a reference to a named struct's definition, adding it
+// to $defs, or the inline schema of an anonymous struct
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	name := KindOf(t)
+	if t.Name() != "" {
+		if _, ok := g.defs[name]; ok {
+			return map[string]interface{}{"$ref": "#/$defs/" + name}
+		}
+		// Reserve the name first so recursive types terminate
+		g.defs[name] = map[string]interface{}{}
+	}
+	
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		
+		name := field.Name
+		omitEmpty := false
+		if tag := field.Tag.Get("json"); tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				if option == "omitempty" {
+					omitEmpty = true
+				}
+			}
+		}
+		
+		properties[name] = g.schemaFor(field.Type)
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+	
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if t.Name() == "" {
+		return schema
+	}
+	g.defs[name] = schema
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+// kebabCase converts a kind such as "MonitorStatus" to "monitor-status"
+func kebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,383 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used to colorize table cells
+const (
+	colorReset  = "\x1b[39m"
+	highlight   = "\x1b[7m"
+	resetAll    = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// minFittedColumnWidth is the narrowest a column is shrunk to when fitting a
+// table to the terminal width
+const minFittedColumnWidth = 8
+
+// tableWriter aligns tab-separated cells into columns. Unlike text/tabwriter
+// it measures cells by their display width, so wide characters and colored
+// cells line up, and it can shrink columns to fit the terminal. It honors the
+// text/tabwriter AlignRight, Debug, DiscardEmptyColumns and TabIndent flags.
+type tableWriter struct {
+	out     io.Writer
+	buf     bytes.Buffer
+	padding int
+	padChar byte
+	// minWidth is the minimum width of a cell including its padding
+	minWidth int
+	// tabWidth is the width of a tab when cells are padded with tabs
+	tabWidth int
+	// flags are text/tabwriter formatting flags
+	flags uint
+	// header marks the first line as column headers, used to pick colors
+	header bool
+	// maxWidth is the total width the table is fitted to (0 means no limit)
+	maxWidth int
+	// color enables state colors
+	color bool
+	// diff highlights rows that changed since the previous watch tick
+	diff *rowDiff
+}
+
+// createTabWriter creates a table writer with the formatter's options
+func (f *Formatter) createTabWriter() *tableWriter {
+	padding := f.TableOptions.Padding
+	if padding < 1 {
+		padding = 1
+	}
+	padChar := f.TableOptions.PadChar
+	if padChar == 0 || (padChar == '\t' && f.TableOptions.TabWidth < 1) {
+		padChar = ' '
+	}
+	return &tableWriter{
+		out:      f.Writer,
+		padding:  padding,
+		padChar:  padChar,
+		minWidth: f.TableOptions.MinWidth,
+		tabWidth: f.TableOptions.TabWidth,
+		flags:    f.TableOptions.Flags,
+		header:   f.TableOptions.Header,
+		maxWidth: f.termWidth,
+		color:    f.useColor(),
+		diff:     f.diff,
+	}
+}
+
+// Write buffers table text until Flush
+func (t *tableWriter) Write(p []byte) (int, error) {
+	return t.buf.Write(p)
+}
+
+// Flush aligns the buffered rows and writes them out
+func (t *tableWriter) Flush() error {
+	text := strings.TrimSuffix(t.buf.String(), "\n")
+	t.buf.Reset()
+	if text == "" {
+		return nil
+	}
+	
+	var rows [][]string
+	for _, line := range strings.Split(text, "\n") {
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+	
+	// Measure every column
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := DisplayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	
+	// Columns that are empty in every row take no space
+	discard := make([]bool, len(widths))
+	if t.flags&tabwriter.DiscardEmptyColumns != 0 {
+		for i, w := range widths {
+			discard[i] = w == 0
+		}
+	}
+	t.fit(widths, discard)
+	
+	var headers []string
+	headerRows := 0
+	if t.header {
+		headers = rows[0]
+		headerRows = 1
+	}
+	
+	var changed map[int]bool
+	if t.diff != nil {
+		changed = t.diff.record(rows[headerRows:])
+	}
+	
+	var out strings.Builder
+	for r, row := range rows {
+		highlighted := t.color && changed[r-headerRows]
+		if highlighted {
+			out.WriteString(highlight)
+		}
+		indent := t.flags&tabwriter.TabIndent != 0
+		for i, cell := range row {
+			if discard[i] {
+				continue
+			}
+			last := i == len(row)-1
+			
+			// Leading empty cells are written as tabs with TabIndent
+			if indent && cell == "" && !last {
+				out.WriteString("\t")
+				continue
+			}
+			indent = false
+			
+			cell = TruncateDisplay(cell, widths[i])
+			width := DisplayWidth(cell)
+			if t.color && r > 0 && i < len(headers) {
+				cell = Colorize(headers[i], cell)
+			}
+			
+			// The last cell of a row is not padded
+			if last {
+				out.WriteString(cell)
+				break
+			}
+			pad := t.pad(t.cellWidth(widths[i]), width)
+			if t.flags&tabwriter.AlignRight != 0 {
+				out.WriteString(pad + cell)
+			} else {
+				out.WriteString(cell + pad)
+			}
+			if t.flags&tabwriter.Debug != 0 {
+				out.WriteString("|")
+			}
+		}
+		if highlighted {
+			out.WriteString(resetAll)
+		}
+		out.WriteString("\n")
+	}
+	
+	_, err := io.WriteString(t.out, out.String())
+	return err
+}
+
+// cellWidth returns the width of a column of the given text width once
+// padded, rounded up to a whole number of tabs when padding with tabs
+func (t *tableWriter) cellWidth(width int) int {
+	width += t.padding
+	if width < t.minWidth {
+		width = t.minWidth
+	}
+	if t.padChar == '\t' {
+		width = (width + t.tabWidth - 1) / t.tabWidth * t.tabWidth
+	}
+	return width
+}
+
+// pad returns the padding that fills a cell of the given text width out to
+// cellWidth
+func (t *tableWriter) pad(cellWidth, width int) string {
+	if t.padChar == '\t' {
+		return strings.Repeat("\t", (cellWidth-width+t.tabWidth-1)/t.tabWidth)
+	}
+	return strings.Repeat(string(t.padChar), cellWidth-width)
+}
+
+// fit shrinks the widest columns until the table fits maxWidth
+func (t *tableWriter) fit(widths []int, discard []bool) {
+	if t.maxWidth <= 0 || len(widths) == 0 {
+		return
+	}
+	
+	for t.totalWidth(widths, discard) > t.maxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minFittedColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+// totalWidth returns the width of a table row with the given column widths
+func (t *tableWriter) totalWidth(widths []int, discard []bool) int {
+	total := 0
+	for i, w := range widths {
+		switch {
+		case discard[i]:
+		case i == len(widths)-1:
+			total += w
+		default:
+			total += t.cellWidth(w)
+		}
+	}
+	return total
+}
+
+// escapeCell replaces tabs, newlines and other control characters in a table
+// cell with escape sequences, so a value cannot break the table's columns or
+// rows or send control codes to the terminal
+func escapeCell(s string) string {
+	clean := true
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+	
+	var out strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out.WriteString(`\t`)
+		case r == '\n':
+			out.WriteString(`\n`)
+		case r == '\r':
+			out.WriteString(`\r`)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&out, `\x%02x`, r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// Colorize colors a cell based on its column: monitor states in state columns
+// and false in the up column
+func Colorize(header, cell string) string {
+	var color string
+	switch strings.ToLower(header) {
+	case "status", "state", "overall_state", "in_state":
+		switch strings.ToLower(strings.TrimSpace(cell)) {
+		case "alert":
+			color = colorRed
+		case "warn":
+			color = colorYellow
+		case "ok":
+			color = colorGreen
+		}
+	case "up":
+		if strings.TrimSpace(cell) == "false" {
+			color = colorRed
+		}
+	}
+	
+	if color == "" {
+		return cell
+	}
+	return color + cell + colorReset
+}
+
+// DisplayWidth returns the number of terminal columns s occupies, ignoring
+// ANSI escape sequences
+func DisplayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// TruncateDisplay shortens s to at most width terminal columns, marking the
+// cut with "..." and never splitting a multi-byte character
+func TruncateDisplay(s string, width int) string {
+	if width <= 0 || DisplayWidth(s) <= width {
+		return s
+	}
+	
+	const ellipsis = "..."
+	if width <= len(ellipsis) {
+		return ellipsis[:width]
+	}
+	
+	limit := width - len(ellipsis)
+	used := 0
+	var out strings.Builder
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > limit {
+			break
+		}
+		out.WriteRune(r)
+		used += w
+	}
+	return out.String() + ellipsis
+}
+
+// ansiSequenceLength returns the length of the ANSI CSI sequence at the start
+// of s, or 0 if there is none
+func ansiSequenceLength(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// wideRanges lists the East Asian wide and emoji ranges that take two columns
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK radicals, punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, CJK symbols
+	{0x3400, 0x4DBF},   // CJK Extension A
+	{0x4E00, 0x9FFF},   // CJK Unified Ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul Syllables
+	{0xF900, 0xFAFF},   // CJK Compatibility Ideographs
+	{0xFE30, 0xFE4F},   // CJK Compatibility Forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F300, 0x1F64F}, // Symbols, pictographs and emoticons
+	{0x1F900, 0x1F9FF}, // Supplemental symbols and pictographs
+	{0x20000, 0x3FFFD}, // CJK Extensions B and beyond
+}
+
+// runeWidth returns the number of terminal columns r occupies
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.IsControl(r):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+	return 1
+}
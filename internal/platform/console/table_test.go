@@ -0,0 +1,137 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+func TestTruncateDisplay(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		width int
+		want  string
+	}{
+		{name: "fits", value: "web-01", width: 10, want: "web-01"},
+		{name: "ascii", value: "abcdefghij", width: 8, want: "abcde..."},
+		{name: "multi-byte", value: "ñandú-ñandú-ñandú", width: 8, want: "ñandú..."},
+		{name: "wide characters", value: "東京都の本番サーバー", width: 9, want: "東京都..."},
+		{name: "no limit", value: "abcdefghij", width: 0, want: "abcdefghij"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateDisplay(tt.value, tt.width)
+			if got != tt.want {
+				t.Errorf("TruncateDisplay() = %q, want %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("TruncateDisplay() returned invalid UTF-8 %q", got)
+			}
+		})
+	}
+}
+
+func TestTableWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &tableWriter{out: &buf, padding: 1, padChar: ' ', header: true, color: true}
+	w.Write([]byte("name\tstatus\tup\n東京\tAlert\tfalse\nweb-01\tOK\ttrue\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"name   status up",
+		"東京   " + colorRed + "Alert" + colorReset + "  " + colorRed + "false" + colorReset,
+		"web-01 " + colorGreen + "OK" + colorReset + "     true",
+	}
+	for i := range want {
+		if i >= len(lines) || lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines, want[i])
+		}
+	}
+}
+
+func TestTableWriter_FitsWidth(t *testing.T) {
+	var buf bytes.Buffer
+	w := &tableWriter{out: &buf, padding: 1, padChar: ' ', maxWidth: 30}
+	w.Write([]byte("name\tdescription\nweb-01\t" + strings.Repeat("x", 60) + "\n"))
+	w.Flush()
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if width := DisplayWidth(line); width > 30 {
+			t.Errorf("line %q is %d columns wide, want at most 30", line, width)
+		}
+	}
+}
+
+func TestTableWriter_Options(t *testing.T) {
+	tests := []struct {
+		name string
+		w    tableWriter
+		want string
+	}{
+		{name: "min width", w: tableWriter{padding: 1, padChar: ' ', minWidth: 6}, want: "a     bb    c\nccc   d     e\n"},
+		{name: "tabs", w: tableWriter{padding: 1, padChar: '\t', tabWidth: 4}, want: "a\tbb\tc\nccc\td\te\n"},
+		{name: "align right", w: tableWriter{padding: 1, padChar: ' ', flags: tabwriter.AlignRight}, want: "   a bbc\n ccc  de\n"},
+		{name: "debug", w: tableWriter{padding: 1, padChar: ' ', flags: tabwriter.Debug}, want: "a   |bb |c\nccc |d  |e\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := tt.w
+			w.out = &buf
+			w.Write([]byte("a\tbb\tc\nccc\td\te\n"))
+			w.Flush()
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Flush() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatter_EscapesCells(t *testing.T) {
+	f, err := NewFormatter("table")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	rows := []columnsRow{{Name: "web\t01", Apps: []string{"line1\nline2\x1b[2J"}}}
+	f.Columns = []string{"name", "apps"}
+	if err := f.Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Format() produced %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if want := `web\t01 line1\nline2\x1b[2J`; lines[1] != want {
+		t.Errorf("row = %q, want %q", lines[1], want)
+	}
+}
+
+func TestFormatter_SliceCells(t *testing.T) {
+	f, err := NewFormatter("table")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	rows := []columnsRow{{Name: "web-01", Apps: []string{"nginx", "redis"}}}
+	f.Columns = []string{"name", "apps"}
+	if err := f.Format(rows); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "nginx, redis") {
+		t.Errorf("Format() = %q, want slice rendered as a comma list", buf.String())
+	}
+}
@@ -0,0 +1,187 @@
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the helpers available to go-template output
+var templateFuncs = template.FuncMap{
+	"join":       templateJoin,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"toJSON":     templateToJSON,
+	"formatTime": templateFormatTime,
+	"since":      templateSince,
+}
+
+// ParseGoTemplate compiles a go-template for output, with the extra helpers
+// join, upper, lower, toJSON, formatTime and since
+func ParseGoTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// ReadTemplateFile reads a go-template from a file
+func ReadTemplateFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading template file: %v", err)
+	}
+	return string(data), nil
+}
+
+// formatGoTemplate formats data with the formatter's go-template. Data is
+// converted to its JSON representation first so templates use the same field
+// names as JSON output (e.g., {{.host_name}}).
+func (f *Formatter) formatGoTemplate(data interface{}) error {
+	tmpl, err := ParseGoTemplate(f.Template)
+	if err != nil {
+		return err
+	}
+	
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+	
+	if err := tmpl.Execute(f.Writer, generic); err != nil {
+		return fmt.Errorf("error executing go-template: %v", err)
+	}
+	return nil
+}
+
+// formatJSONPath formats data with the formatter's JSONPath template
+func (f *Formatter) formatJSONPath(data interface{}) error {
+	jsonPath, err := ParseJSONPath(f.Template)
+	if err != nil {
+		return err
+	}
+	
+	generic, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+	
+	var buf bytes.Buffer
+	if err := jsonPath.Execute(&buf, generic); err != nil {
+		return err
+	}
+	
+	// End the output with a newline unless the template already does
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err = f.Writer.Write(buf.Bytes())
+	return err
+}
+
+// toGeneric converts data to the generic value produced by decoding its JSON
+// representation. Numbers are kept as json.Number so large IDs print exactly.
+func toGeneric(data interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding to JSON: %v", err)
+	}
+	
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("error decoding JSON: %v", err)
+	}
+	return generic, nil
+}
+
+// sortedKeys returns the keys of a generic JSON object in order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// templateJoin joins a list with a separator: {{join .tags ","}}
+func templateJoin(list interface{}, sep string) string {
+	switch v := list.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, sep)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = jsonPathString(item)
+		}
+		return strings.Join(parts, sep)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// templateToJSON renders a value as compact JSON: {{toJSON .options}}
+func templateToJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseTemplateTime accepts RFC3339 strings and Unix timestamps in seconds
+func parseTemplateTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(time.RFC3339, v)
+	case json.Number:
+		seconds, err := v.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case int:
+		return time.Unix(int64(v), 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("cannot interpret %v as a time", value)
+	}
+}
+
+// templateFormatTime formats a timestamp with a Go layout: {{formatTime "2006-01-02 15:04" .last_reported_at}}
+func templateFormatTime(layout string, value interface{}) (string, error) {
+	if value == nil || value == "" {
+		return "", nil
+	}
+	t, err := parseTemplateTime(value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// templateSince returns the time elapsed since a timestamp: {{since .last_reported_at}}
+func templateSince(value interface{}) (string, error) {
+	if value == nil || value == "" {
+		return "", nil
+	}
+	t, err := parseTemplateTime(value)
+	if err != nil {
+		return "", err
+	}
+	return time.Since(t).Truncate(time.Second).String(), nil
+}
@@ -0,0 +1,114 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+)
+
+type templateRow struct {
+	HostName string   `json:"host_name"`
+	ID       int64    `json:"id"`
+	Tags     []string `json:"tags"`
+}
+
+func TestFormatter_Templates(t *testing.T) {
+	rows := []templateRow{
+		{HostName: "web-01", ID: 1234567890123, Tags: []string{"env:prod", "role:web"}},
+		{HostName: "db-01", ID: 2},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "jsonpath wildcard",
+			output: "jsonpath={.[*].host_name}",
+			want:   "web-01 db-01\n",
+		},
+		{
+			name:   "jsonpath range",
+			output: `jsonpath={range .[*]}{.host_name}{"\t"}{.id}{"\n"}{end}`,
+			want:   "web-01\t1234567890123\ndb-01\t2\n",
+		},
+		{
+			name:   "jsonpath index and negative slice",
+			output: "jsonpath={.[0].tags[-1:]}",
+			want:   "role:web\n",
+		},
+		{
+			name:   "go-template with helpers",
+			output: `go-template={{range .}}{{upper .host_name}} {{join .tags ","}}{{"\n"}}{{end}}`,
+			want:   "WEB-01 env:prod,role:web\nDB-01 \n",
+		},
+		{
+			name:   "template alias with toJSON",
+			output: `template={{(index . 0).tags | toJSON}}`,
+			want:   `["env:prod","role:web"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.output)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			var buf bytes.Buffer
+			f.WithWriter(&buf)
+
+			if err := f.Format(rows); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPath_Errors(t *testing.T) {
+	for _, template := range []string{"{.name", "{range .[*]}{.name}", "{end}", "{.[abc]}"} {
+		if _, err := ParseJSONPath(template); err == nil {
+			t.Errorf("ParseJSONPath(%q) expected an error", template)
+		}
+	}
+}
+
+func TestParseJSONPath_Quotes(t *testing.T) {
+	data := map[string]interface{}{"it's": "apostrophe", `say "hi"`: "double", "a}b]": "braces"}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: `{.["it's"]}`, want: "apostrophe"},
+		{template: `{.['say "hi"']}`, want: "double"},
+		{template: `{.['a}b]']}`, want: "braces"},
+	}
+
+	for _, tt := range tests {
+		jsonPath, err := ParseJSONPath(tt.template)
+		if err != nil {
+			t.Errorf("ParseJSONPath(%q) error = %v", tt.template, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := jsonPath.Execute(&buf, data); err != nil {
+			t.Errorf("Execute(%q) error = %v", tt.template, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("Execute(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestNewFormatter_RejectsMalformedTemplates(t *testing.T) {
+	for _, output := range []string{"go-template={{.name", "jsonpath={.name", "custom-columns=NAME"} {
+		if _, err := NewFormatter(output); err == nil {
+			t.Errorf("NewFormatter(%q) expected an error", output)
+		}
+	}
+}
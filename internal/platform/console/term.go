@@ -0,0 +1,92 @@
+package console
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Stderr writes diagnostics meant for the terminal, such as local logs. A
+// full-screen view holds it while it owns the terminal so that diagnostics do
+// not corrupt the screen.
+var Stderr = &TerminalWriter{out: os.Stderr}
+
+// TerminalWriter writes to a terminal unless it is held
+type TerminalWriter struct {
+	mu   sync.Mutex
+	out  io.Writer
+	held int
+}
+
+// Write implements io.Writer. Writes made while the writer is held are
+// discarded.
+func (w *TerminalWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	
+	if w.held > 0 {
+		return len(p), nil
+	}
+	return w.out.Write(p)
+}
+
+// Hold discards writes until the returned release function is called
+func (w *TerminalWriter) Hold() (release func()) {
+	w.mu.Lock()
+	w.held++
+	w.mu.Unlock()
+	
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			w.mu.Lock()
+			w.held--
+			w.mu.Unlock()
+		})
+	}
+}
+
+// IsTerminal reports whether w writes to an interactive terminal
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalWidth returns the width in columns of the terminal w writes to, or
+// 0 if w is not a terminal. The COLUMNS environment variable is used when the
+// terminal size cannot be queried.
+func TerminalWidth(w io.Writer) int {
+	width, _ := TerminalSize(w)
+	return width
+}
+
+// TerminalSize returns the width and height of the terminal w writes to, or
+// zeros if w is not a terminal. The COLUMNS and LINES environment variables
+// are used when the terminal size cannot be queried.
+func TerminalSize(w io.Writer) (int, int) {
+	if !IsTerminal(w) {
+		return 0, 0
+	}
+	width, height := terminalSize(w.(*os.File))
+	if width <= 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	if height <= 0 {
+		height, _ = strconv.Atoi(os.Getenv("LINES"))
+	}
+	return max(width, 0), max(height, 0)
+}
+
+// colorDisabledByEnv reports whether the NO_COLOR convention disables color
+// (see https://no-color.org)
+func colorDisabledByEnv() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
@@ -0,0 +1,9 @@
+package console
+
+import "syscall"
+
+// termios ioctl requests on macOS
+const (
+	ioctlReadTermios  = syscall.TIOCGETA
+	ioctlWriteTermios = syscall.TIOCSETA
+)
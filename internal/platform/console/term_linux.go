@@ -0,0 +1,9 @@
+package console
+
+import "syscall"
+
+// termios ioctl requests on Linux
+const (
+	ioctlReadTermios  = syscall.TCGETS
+	ioctlWriteTermios = syscall.TCSETS
+)
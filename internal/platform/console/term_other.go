@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package console
+
+import (
+	"errors"
+	"os"
+)
+
+// terminalSize is not supported on this platform; callers fall back to COLUMNS
+func terminalSize(file *os.File) (int, int) {
+	return 0, 0
+}
+
+// MakeRaw is not supported on this platform
+func MakeRaw(file *os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
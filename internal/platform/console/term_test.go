@@ -0,0 +1,24 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTerminalWriter_Hold(t *testing.T) {
+	var out bytes.Buffer
+	w := &TerminalWriter{out: &out}
+
+	w.Write([]byte("before\n"))
+	release := w.Hold()
+	if n, err := w.Write([]byte("held\n")); n != 5 || err != nil {
+		t.Errorf("Write() while held = %d, %v, want 5, nil", n, err)
+	}
+	release()
+	release()
+	w.Write([]byte("after\n"))
+
+	if got := out.String(); got != "before\nafter\n" {
+		t.Errorf("output = %q, want writes made while held to be discarded", got)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build linux || darwin
+
+package console
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize mirrors the kernel's struct winsize
+type winsize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+// terminalSize queries the width and height of the terminal behind file
+func terminalSize(file *os.File) (int, int) {
+	var ws winsize
+	if err := ioctl(file.Fd(), syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return 0, 0
+	}
+	return int(ws.Col), int(ws.Row)
+}
+
+// MakeRaw puts the terminal behind file into raw mode so single key presses
+// can be read, and returns a function that restores the previous mode
+func MakeRaw(file *os.File) (func(), error) {
+	var original syscall.Termios
+	if err := ioctl(file.Fd(), ioctlReadTermios, unsafe.Pointer(&original)); err != nil {
+		return nil, fmt.Errorf("failed to read terminal mode: %v", err)
+	}
+	
+	raw := original
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	
+	if err := ioctl(file.Fd(), ioctlWriteTermios, unsafe.Pointer(&raw)); err != nil {
+		return nil, fmt.Errorf("failed to set raw terminal mode: %v", err)
+	}
+	
+	return func() {
+		ioctl(file.Fd(), ioctlWriteTermios, unsafe.Pointer(&original))
+	}, nil
+}
+
+// ioctl performs an ioctl system call with a pointer argument
+func ioctl(fd uintptr, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
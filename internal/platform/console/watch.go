@@ -0,0 +1,100 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rowDiff remembers the rows of the tables drawn on the previous watch tick so
+// rows that changed can be highlighted. Rows are identified by their first cell.
+type rowDiff struct {
+	ticks    int
+	previous []map[string]string
+	current  []map[string]string
+}
+
+// nextTick starts a new redraw, keeping the tables just drawn for comparison
+func (d *rowDiff) nextTick() {
+	if d.current != nil {
+		d.ticks++
+		d.previous = d.current
+	}
+	d.current = nil
+}
+
+// record stores the data rows of a table and reports which rows changed since
+// the same table was drawn on the previous tick. Nothing is highlighted on the
+// first tick.
+func (d *rowDiff) record(rows [][]string) map[int]bool {
+	var previous map[string]string
+	if table := len(d.current); table < len(d.previous) {
+		previous = d.previous[table]
+	}
+	
+	current := make(map[string]string, len(rows))
+	changed := make(map[int]bool)
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		line := strings.Join(row, "\t")
+		current[row[0]] = line
+		if d.ticks > 0 && previous[row[0]] != line {
+			changed[i] = true
+		}
+	}
+	
+	d.current = append(d.current, current)
+	return changed
+}
+
+// Watch calls render every interval until ctx is canceled, redrawing table
+// output in place and highlighting rows that changed since the previous tick.
+// Table output that is not written to a terminal, and other output formats,
+// are written one after another; tables are separated by a header line. Canceling ctx, for
+// example with Ctrl-C, ends the watch without an error.
+func Watch(ctx context.Context, f *Formatter, interval time.Duration, title string, render func() error) error {
+	f.diff = &rowDiff{}
+	defer func() { f.diff = nil }()
+	
+	terminal := IsTerminal(f.Writer)
+	first := true
+	for {
+		f.diff.nextTick()
+		
+		if f.OutFormat == TableFormat {
+			header := fmt.Sprintf("Every %s: %s (%s)", interval, title, time.Now().Format(time.RFC3339))
+			if terminal {
+				// Clear the screen and move the cursor home before redrawing
+				fmt.Fprint(f.Writer, "\033[H\033[2J")
+				fmt.Fprintf(f.Writer, "%s\n\n", header)
+			} else {
+				// Piped output keeps every refresh, separated by a line
+				if !first {
+					fmt.Fprintln(f.Writer)
+				}
+				fmt.Fprintf(f.Writer, "--- %s ---\n", header)
+			}
+		}
+		first = false
+		
+		if err := render(); err != nil {
+			// Requests aborted by the interrupt are not errors
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		
+		if ctx.Err() != nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
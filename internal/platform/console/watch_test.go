@@ -0,0 +1,87 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowDiff(t *testing.T) {
+	d := &rowDiff{}
+
+	d.nextTick()
+	if changed := d.record([][]string{{"cpu", "OK"}, {"disk", "OK"}}); len(changed) != 0 {
+		t.Errorf("first tick changed = %v, want none", changed)
+	}
+
+	d.nextTick()
+	changed := d.record([][]string{{"cpu", "Alert"}, {"disk", "OK"}, {"memory", "OK"}})
+	if !changed[0] || changed[1] || !changed[2] {
+		t.Errorf("second tick changed = %v, want rows 0 and 2", changed)
+	}
+
+	// A second table on the same tick is compared with its own previous rows
+	if changed := d.record([][]string{{"web-01", "true"}}); !changed[0] {
+		t.Errorf("new table changed = %v, want row 0", changed)
+	}
+}
+
+func TestWatch_StopsOnCancel(t *testing.T) {
+	f, err := NewFormatter("json")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	err = Watch(ctx, f, time.Millisecond, "test", func() error {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		return f.Format([]string{"tick"})
+	})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("Watch() ran %d times, want 3", runs)
+	}
+	if got := strings.Count(buf.String(), "tick"); got != 3 {
+		t.Errorf("Watch() wrote %d renders, want 3", got)
+	}
+}
+
+func TestWatch_PipedTableOutput(t *testing.T) {
+	f, err := NewFormatter("table")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	var buf bytes.Buffer
+	f.WithWriter(&buf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	err = Watch(ctx, f, time.Millisecond, "hosts list", func() error {
+		runs++
+		if runs == 2 {
+			cancel()
+		}
+		return f.Format([]string{"tick"})
+	})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "\033[") {
+		t.Errorf("Watch() wrote escape sequences to a pipe: %q", out)
+	}
+	if got := strings.Count(out, "--- Every 1ms: hosts list ("); got != 2 {
+		t.Errorf("Watch() wrote %d separators, want 2:\n%s", got, out)
+	}
+}
@@ -2,19 +2,23 @@ package datadog
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/padawandba/datadog-cli/internal/platform/config"
 )
 
-// NewClient creates a new Datadog API client
-func NewClient(cfg *config.Config) (*datadog.APIClient, context.Context) {
-	// Create a context with timeout for API operations
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// NewClient creates a new Datadog API client whose context is derived from parent,
+// so that cancelling parent (e.g., on SIGINT) aborts in-flight API operations.
+// Individual requests are bounded by the HTTP client timeout.
+func NewClient(parent context.Context, cfg *config.Config) (*datadog.APIClient, context.Context) {
+	// Create a cancellable context for API operations
+	ctx, cancel := context.WithCancel(parent)
 	
 	// Store the cancel function in the context for cleanup
 	ctx = context.WithValue(ctx, "cancelFunc", cancel)
@@ -41,11 +45,19 @@ func NewClient(cfg *config.Config) (*datadog.APIClient, context.Context) {
 	configuration.AddDefaultHeader("DD-API-KEY", cfg.APIKey)
 	configuration.AddDefaultHeader("DD-APPLICATION-KEY", cfg.AppKey)
 	
+	// Bodies are captured for --trace-http only if they can be redacted
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		slog.Warn("HTTP tracing disabled", "error", err)
+	}
+	
 	// Configure HTTP client with reasonable timeouts
 	httpClient := &http.Client{
 		Timeout: 30 * time.Second,
 		Transport: &loggingTransport{
 			transport: http.DefaultTransport,
+			cfg:       cfg,
+			redactor:  redactor,
 		},
 	}
 	configuration.HTTPClient = httpClient
@@ -61,26 +73,70 @@ func NewClient(cfg *config.Config) (*datadog.APIClient, context.Context) {
 }
 
 // loggingTransport is an http.RoundTripper that logs requests and responses
+// and reports them to the default Telemetry. With cfg.TraceHTTP set, it also
+// writes each redacted exchange, with its bodies, to trace, or to the output
+// set with SetHTTPTraceOutput if trace is nil.
 type loggingTransport struct {
 	transport http.RoundTripper
+	cfg       *config.Config
+	redactor  *Redactor
+	
+	traceMu sync.Mutex
+	trace   io.Writer
+}
+
+// traceOutput returns where exchanges are written, or nil if they are not
+func (t *loggingTransport) traceOutput() io.Writer {
+	if t.cfg == nil || !t.cfg.TraceHTTP || t.redactor == nil {
+		return nil
+	}
+	if t.trace != nil {
+		return t.trace
+	}
+	return currentHTTPTraceOutput()
 }
 
 // RoundTrip implements the http.RoundTripper interface
 func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Start a span for the call; logs written with ctx carry its IDs
+	telemetry := DefaultTelemetry()
+	span, ctx := telemetry.startAPICall(req.Context(), req)
+	
 	// Log the request
-	slog.Debug("Datadog API request",
+	slog.DebugContext(ctx, "Datadog API request",
 		"method", req.Method,
 		"url", req.URL.String(),
 	)
 	
+	// Capture the request body before it is sent
+	trace := t.traceOutput()
+	var body []byte
+	if trace != nil {
+		body = requestBody(req)
+	}
+	
 	// Perform the request
 	start := time.Now()
 	resp, err := t.transport.RoundTrip(req)
 	duration := time.Since(start)
+	telemetry.finishAPICall(span, req, resp, err, duration)
+	
+	if trace != nil {
+		exchange := &httpExchange{req: req, requestBody: body, resp: resp, err: err, duration: duration}
+		if resp != nil {
+			exchange.responseBody = responseBody(resp)
+		}
+		
+		t.traceMu.Lock()
+		if err := writeHTTPTrace(trace, t.redactor, exchange, t.cfg.HTTPBodyLimit); err != nil {
+			slog.DebugContext(ctx, "Failed to write HTTP trace", "error", err)
+		}
+		t.traceMu.Unlock()
+	}
 	
 	// Handle transport-level errors
 	if err != nil {
-		slog.Error("Datadog API transport error",
+		slog.ErrorContext(ctx, "Datadog API transport error",
 			"method", req.Method,
 			"url", req.URL.String(),
 			"error", err,
@@ -97,13 +153,20 @@ func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 		level = slog.LevelWarn
 	}
 	
-	slog.Log(context.Background(), level, "Datadog API response",
+	attrs := []any{
 		"method", req.Method,
 		"url", req.URL.String(),
 		"status", resp.Status,
 		"status_code", resp.StatusCode,
 		"duration_ms", duration.Milliseconds(),
-	)
+	}
+	if id := resp.Header.Get(requestIDHeader); id != "" {
+		attrs = append(attrs, "request_id", id)
+	}
+	if remaining := resp.Header.Get(rateLimitHeaderPrefix + "Remaining"); remaining != "" {
+		attrs = append(attrs, "rate_limit_remaining", remaining)
+	}
+	slog.Log(ctx, level, "Datadog API response", attrs...)
 	
 	return resp, nil
 }
@@ -0,0 +1,208 @@
+package datadog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+)
+
+// Exit codes returned by the CLI for each class of failure
+const (
+	ExitError          = 1
+	ExitAuth           = 3
+	ExitNotFound       = 4
+	ExitRateLimited    = 5
+	ExitPartialFailure = 6
+)
+
+// requestIDHeader is the response header carrying the Datadog request ID
+const requestIDHeader = "X-Request-Id"
+
+// ExitCoder is implemented by errors that map to a specific exit code
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the exit code for err: the code of the first error in its
+// chain that implements ExitCoder, or ExitError
+func ExitCode(err error) int {
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitError
+}
+
+// APIError is a failed Datadog API call
+type APIError struct {
+	// Operation describes the call, e.g. "listing hosts"
+	Operation string
+	// StatusCode is the HTTP status, or 0 if no response was received
+	StatusCode int
+	// Messages are the error messages from the Datadog response body
+	Messages []string
+	// RequestID identifies the request to Datadog support
+	RequestID string
+	// Err is the underlying client error
+	Err error
+}
+
+// NewAPIError wraps an error returned by the Datadog API client for the given
+// operation, extracting the status, error messages and request ID from the response
+func NewAPIError(operation string, httpResp *http.Response, err error) error {
+	apiErr := &APIError{Operation: operation, Err: err}
+	
+	if httpResp != nil {
+		apiErr.StatusCode = httpResp.StatusCode
+		apiErr.RequestID = httpResp.Header.Get(requestIDHeader)
+	}
+	
+	var openAPIErr datadog.GenericOpenAPIError
+	if errors.As(err, &openAPIErr) {
+		apiErr.Messages = errorMessages(openAPIErr.Body())
+		
+		// Paginated calls return no response; the message starts with the status (e.g., "404 Not Found")
+		if apiErr.StatusCode == 0 {
+			if code, _, ok := strings.Cut(openAPIErr.Error(), " "); ok {
+				apiErr.StatusCode, _ = strconv.Atoi(code)
+			}
+		}
+	}
+	
+	return apiErr
+}
+
+// errorMessages extracts the messages of a Datadog error response body, which
+// is either {"errors": ["..."]} or JSON:API style {"errors": [{"detail": "..."}]}
+func errorMessages(body []byte) []string {
+	var response struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+	
+	messages := make([]string, 0, len(response.Errors))
+	for _, raw := range response.Errors {
+		var message string
+		if err := json.Unmarshal(raw, &message); err == nil {
+			messages = append(messages, message)
+			continue
+		}
+		
+		var object struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		}
+		if err := json.Unmarshal(raw, &object); err == nil {
+			switch {
+			case object.Detail != "":
+				messages = append(messages, object.Detail)
+			case object.Title != "":
+				messages = append(messages, object.Title)
+			}
+		}
+	}
+	return messages
+}
+
+// Error formats the error like "error listing hosts (status: 403): 403 Forbidden: Forbidden"
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("error " + e.Operation)
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " (status: %d)", e.StatusCode)
+	}
+	b.WriteString(": " + e.Err.Error())
+	if len(e.Messages) > 0 {
+		b.WriteString(": " + strings.Join(e.Messages, "; "))
+	}
+	if e.RequestID != "" {
+		b.WriteString(" (request ID: " + e.RequestID + ")")
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying client error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode maps the HTTP status to the CLI's exit codes
+func (e *APIError) ExitCode() int {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitAuth
+	case http.StatusNotFound:
+		return ExitNotFound
+	case http.StatusTooManyRequests:
+		return ExitRateLimited
+	default:
+		return ExitError
+	}
+}
+
+// PartialFailureError reports that some items of a bulk operation failed
+type PartialFailureError struct {
+	Failed int
+	Total  int
+	// Noun names the items, e.g. "monitors"
+	Noun string
+}
+
+// Error formats the error like "2 of 5 monitors failed"
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%d of %d %s failed", e.Failed, e.Total, e.Noun)
+}
+
+// ExitCode is ExitPartialFailure
+func (e *PartialFailureError) ExitCode() int {
+	return ExitPartialFailure
+}
+
+// ErrorReport is the machine-readable form of a command error, printed to
+// stderr as JSON when JSON output is selected
+type ErrorReport struct {
+	Error      string   `json:"error"`
+	Kind       string   `json:"kind"`
+	ExitCode   int      `json:"exit_code"`
+	Operation  string   `json:"operation,omitempty"`
+	StatusCode int      `json:"status_code,omitempty"`
+	Messages   []string `json:"messages,omitempty"`
+	RequestID  string   `json:"request_id,omitempty"`
+}
+
+// NewErrorReport describes err for automation
+func NewErrorReport(err error) ErrorReport {
+	report := ErrorReport{
+		Error:    err.Error(),
+		ExitCode: ExitCode(err),
+	}
+	
+	switch report.ExitCode {
+	case ExitAuth:
+		report.Kind = "auth"
+	case ExitNotFound:
+		report.Kind = "not_found"
+	case ExitRateLimited:
+		report.Kind = "rate_limited"
+	case ExitPartialFailure:
+		report.Kind = "partial_failure"
+	default:
+		report.Kind = "error"
+	}
+	
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		report.Operation = apiErr.Operation
+		report.StatusCode = apiErr.StatusCode
+		report.Messages = apiErr.Messages
+		report.RequestID = apiErr.RequestID
+	}
+	return report
+}
@@ -0,0 +1,79 @@
+package datadog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantCode     int
+		wantMessages []string
+	}{
+		{name: "forbidden", status: 403, body: `{"errors": ["Forbidden"]}`, wantCode: ExitAuth, wantMessages: []string{"Forbidden"}},
+		{name: "unauthorized", status: 401, body: `{"errors": ["Unauthorized"]}`, wantCode: ExitAuth, wantMessages: []string{"Unauthorized"}},
+		{name: "not found", status: 404, body: `{"errors": [{"title": "Not Found", "detail": "downtime abc does not exist"}]}`, wantCode: ExitNotFound, wantMessages: []string{"downtime abc does not exist"}},
+		{name: "rate limited", status: 429, body: `{"errors": ["Rate limit exceeded"]}`, wantCode: ExitRateLimited, wantMessages: []string{"Rate limit exceeded"}},
+		{name: "server error", status: 500, body: `not json`, wantCode: ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpResp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			httpResp.Header.Set("X-Request-Id", "req-123")
+			clientErr := datadog.GenericOpenAPIError{
+				ErrorBody:    []byte(tt.body),
+				ErrorMessage: fmt.Sprintf("%d %s", tt.status, http.StatusText(tt.status)),
+			}
+
+			// Commands wrap client errors, which must keep the exit code
+			err := fmt.Errorf("failed to list hosts: %w", NewAPIError("listing hosts", httpResp, clientErr))
+
+			if got := ExitCode(err); got != tt.wantCode {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.wantCode)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error %v is not an *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.RequestID != "req-123" {
+				t.Errorf("APIError = %+v", apiErr)
+			}
+			if len(tt.wantMessages) > 0 && !reflect.DeepEqual(apiErr.Messages, tt.wantMessages) {
+				t.Errorf("Messages = %q, want %q", apiErr.Messages, tt.wantMessages)
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("(status: %d)", tt.status)) {
+				t.Errorf("Error() = %q, want the status", err.Error())
+			}
+		})
+	}
+}
+
+func TestNewAPIError_StatusFromPaginatedError(t *testing.T) {
+	err := NewAPIError("listing downtimes", nil, datadog.GenericOpenAPIError{ErrorMessage: "403 Forbidden"})
+	if got := ExitCode(err); got != ExitAuth {
+		t.Errorf("ExitCode() = %d, want %d", got, ExitAuth)
+	}
+}
+
+func TestNewErrorReport(t *testing.T) {
+	report := NewErrorReport(fmt.Errorf("bulk mute: %w", &PartialFailureError{Failed: 2, Total: 5, Noun: "monitors"}))
+	if report.Kind != "partial_failure" || report.ExitCode != ExitPartialFailure || report.Error != "bulk mute: 2 of 5 monitors failed" {
+		t.Errorf("NewErrorReport() = %+v", report)
+	}
+
+	report = NewErrorReport(errors.New("boom"))
+	if report.Kind != "error" || report.ExitCode != ExitError {
+		t.Errorf("NewErrorReport() = %+v", report)
+	}
+}
@@ -0,0 +1,239 @@
+package datadog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPBodyLimit is the default number of bytes of each request and
+// response body written by --trace-http
+const DefaultHTTPBodyLimit = 4096
+
+// httpTraceOutput is where API clients write --trace-http exchanges
+var httpTraceOutput = struct {
+	sync.Mutex
+	w io.Writer
+}{w: os.Stderr}
+
+// SetHTTPTraceOutput sets where --trace-http exchanges are written, so they
+// follow the configured log destination. They go to stderr until it is
+// called; a nil writer turns tracing off.
+func SetHTTPTraceOutput(w io.Writer) {
+	httpTraceOutput.Lock()
+	defer httpTraceOutput.Unlock()
+	httpTraceOutput.w = w
+}
+
+// currentHTTPTraceOutput returns where --trace-http exchanges are written
+func currentHTTPTraceOutput() io.Writer {
+	httpTraceOutput.Lock()
+	defer httpTraceOutput.Unlock()
+	return httpTraceOutput.w
+}
+
+// traceResponseHeaders are the response headers written by --trace-http,
+// besides the rate limit headers
+var traceResponseHeaders = []string{"Content-Type", requestIDHeader}
+
+// rateLimitHeaderPrefix starts the rate limit headers of Datadog responses:
+// X-RateLimit-Limit, -Period, -Remaining, -Reset and -Name
+const rateLimitHeaderPrefix = "X-Ratelimit-"
+
+// curlHeaderVars are the headers that the curl command reads from environment
+// variables instead of showing their redacted values
+var curlHeaderVars = map[string]string{
+	"Dd-Api-Key":         "DD_API_KEY",
+	"Dd-Application-Key": "DD_APP_KEY",
+}
+
+// curlSkipHeaders are request headers left out of the curl command; curl sets
+// them itself
+var curlSkipHeaders = map[string]bool{
+	"Accept-Encoding":  true,
+	"Content-Encoding": true,
+	"Content-Length":   true,
+	"User-Agent":       true,
+}
+
+// httpExchange is an API call captured for --trace-http
+type httpExchange struct {
+	req          *http.Request
+	requestBody  []byte
+	resp         *http.Response
+	responseBody []byte
+	err          error
+	duration     time.Duration
+}
+
+// requestBody returns a copy of the body of req, decompressed if it was
+// compressed, leaving req able to send it
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	var body io.ReadCloser
+	if req.GetBody != nil {
+		var err error
+		if body, err = req.GetBody(); err != nil {
+			return nil
+		}
+	} else {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		if err != nil {
+			return nil
+		}
+		body = io.NopCloser(bytes.NewReader(data))
+	}
+	defer body.Close()
+
+	data, _ := io.ReadAll(body)
+	return decodeBody(data, req.Header.Get("Content-Encoding"))
+}
+
+// responseBody reads the body of resp and replaces it with a copy, so that
+// the API client can still read it, and returns the body decompressed if it
+// was compressed
+func responseBody(resp *http.Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return decodeBody(data, resp.Header.Get("Content-Encoding"))
+}
+
+// decodeBody decompresses a gzip or deflate body. Bodies that cannot be
+// decompressed are returned as they are.
+func decodeBody(data []byte, encoding string) []byte {
+	var reader io.ReadCloser
+	var err error
+	switch strings.ToLower(encoding) {
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return data
+	}
+	if err != nil {
+		return data
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return data
+	}
+	return decoded
+}
+
+// writeHTTPTrace writes a redacted exchange in the style of curl -v: the
+// request, the response with its request ID and rate limit headers, each body
+// pretty-printed and truncated to limit bytes, and a curl command that
+// repeats the request
+func writeHTTPTrace(w io.Writer, redactor *Redactor, x *httpExchange, limit int) error {
+	if limit <= 0 {
+		limit = DefaultHTTPBodyLimit
+	}
+
+	var buf bytes.Buffer
+	url := redactor.String(x.req.URL.String())
+
+	fmt.Fprintf(&buf, "> %s %s\n", x.req.Method, url)
+	headers := redactor.header(x.req.Header)
+	for _, key := range sortedKeys(headers) {
+		fmt.Fprintf(&buf, "> %s: %s\n", key, strings.Join(headers[key], ", "))
+	}
+	requestBody := redactor.Body(x.requestBody)
+	writeTraceBody(&buf, requestBody, limit)
+
+	switch {
+	case x.err != nil:
+		fmt.Fprintf(&buf, "< error after %d ms: %s\n", x.duration.Milliseconds(), redactor.String(x.err.Error()))
+	case x.resp != nil:
+		fmt.Fprintf(&buf, "< %s (%d ms)\n", x.resp.Status, x.duration.Milliseconds())
+		for _, key := range traceResponseHeaders {
+			if value := x.resp.Header.Get(key); value != "" {
+				fmt.Fprintf(&buf, "< %s: %s\n", key, value)
+			}
+		}
+		for _, key := range sortedKeys(x.resp.Header) {
+			if strings.HasPrefix(key, rateLimitHeaderPrefix) {
+				fmt.Fprintf(&buf, "< %s: %s\n", key, strings.Join(x.resp.Header[key], ", "))
+			}
+		}
+		writeTraceBody(&buf, redactor.Body(x.responseBody), limit)
+	}
+
+	fmt.Fprintf(&buf, "$ %s\n\n", curlCommand(x.req.Method, url, headers, requestBody))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeTraceBody writes a body, pretty-printed if it is JSON, followed by a
+// blank line. Bodies longer than limit are truncated.
+func writeTraceBody(buf *bytes.Buffer, body []byte, limit int) {
+	if len(body) == 0 {
+		buf.WriteString("\n")
+		return
+	}
+
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, body, "", "  ") == nil {
+		body = pretty.Bytes()
+	}
+
+	if len(body) > limit {
+		fmt.Fprintf(buf, "%s\n... (%d more bytes)\n\n", body[:limit], len(body)-limit)
+		return
+	}
+	fmt.Fprintf(buf, "%s\n\n", body)
+}
+
+// curlCommand returns a curl command line that sends a request. API and
+// application keys are read from DD_API_KEY and DD_APP_KEY rather than shown.
+func curlCommand(method, url string, headers http.Header, body []byte) string {
+	args := []string{"curl", "-X", method, shellQuote(url)}
+
+	for _, key := range sortedKeys(headers) {
+		canonical := http.CanonicalHeaderKey(key)
+		if curlSkipHeaders[canonical] {
+			continue
+		}
+		if variable, ok := curlHeaderVars[canonical]; ok {
+			args = append(args, "-H", fmt.Sprintf(`"%s: $%s"`, key, variable))
+			continue
+		}
+		for _, value := range headers[key] {
+			args = append(args, "-H", shellQuote(key+": "+value))
+		}
+	}
+
+	if len(body) > 0 {
+		args = append(args, "--data-raw", shellQuote(string(body)))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,141 @@
+package datadog
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+func TestLoggingTransport_TraceHTTP(t *testing.T) {
+	response := `{"errors":["Invalid query: missing metric"],"query":"` + strings.Repeat("x", 200) + `"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "hunter2") {
+			t.Errorf("server received body %s, want the original body", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", "998")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, response)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: testAPIKey, AppKey: testAppKey, TraceHTTP: true, HTTPBodyLimit: 120}
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace bytes.Buffer
+	client := &http.Client{Transport: &loggingTransport{
+		transport: http.DefaultTransport,
+		cfg:       cfg,
+		redactor:  redactor,
+		trace:     &trace,
+	}}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/monitor", strings.NewReader(`{"name":"it's down","options":{"password":"hunter2"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", testAPIKey)
+	req.Header.Set("DD-APPLICATION-KEY", testAppKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != response {
+		t.Errorf("client read %q, want the whole response", body)
+	}
+
+	out := trace.String()
+	for _, want := range []string{
+		"> POST " + server.URL + "/api/v1/monitor\n",
+		"> Dd-Api-Key: [REDACTED]\n",
+		"{\n  \"name\": \"it's down\",\n  \"options\": {\n    \"password\": \"[REDACTED]\"\n  }\n}\n",
+		"< 400 Bad Request (",
+		"< X-Request-Id: req-123\n",
+		"< X-Ratelimit-Limit: 1000\n< X-Ratelimit-Remaining: 998\n",
+		"\"Invalid query: missing metric\"",
+		"more bytes)\n",
+		`$ curl -X POST '` + server.URL + `/api/v1/monitor' -H 'Content-Type: application/json' -H "Dd-Api-Key: $DD_API_KEY" -H "Dd-Application-Key: $DD_APP_KEY" --data-raw '{"name":"it'\''s down","options":{"password":"[REDACTED]"}}'`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace does not contain %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{testAPIKey, testAppKey, "hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("trace contains secret %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestLoggingTransport_TraceHTTPDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace bytes.Buffer
+	client := &http.Client{Transport: &loggingTransport{transport: http.DefaultTransport, cfg: cfg, redactor: redactor, trace: &trace}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if trace.Len() != 0 {
+		t.Errorf("trace = %q, want nothing without TraceHTTP", trace.String())
+	}
+}
+
+func TestLoggingTransport_TraceHTTPOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{TraceHTTP: true}
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &loggingTransport{transport: http.DefaultTransport, cfg: cfg, redactor: redactor}}
+	get := func() {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	defer SetHTTPTraceOutput(os.Stderr)
+
+	// Exchanges follow the output set by the logging setup...
+	var trace bytes.Buffer
+	SetHTTPTraceOutput(&trace)
+	get()
+	if !strings.Contains(trace.String(), "> GET "+server.URL) {
+		t.Errorf("trace = %q, want the exchange", trace.String())
+	}
+
+	// ...and stop when it is turned off
+	trace.Reset()
+	SetHTTPTraceOutput(nil)
+	get()
+	if trace.Len() != 0 {
+		t.Errorf("trace = %q, want nothing while turned off", trace.String())
+	}
+}
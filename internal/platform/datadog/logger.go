@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
-	"strings"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/padawandba/datadog-cli/internal/platform/config"
 )
@@ -19,11 +21,30 @@ const (
 	// DatadogLogsEndpoint is the endpoint for sending logs to Datadog
 	DatadogLogsEndpoint = "https://http-intake.logs.%s/api/v2/logs"
 	
-	// BatchSize is the number of logs to batch before sending
-	BatchSize = 20
+	// BatchSize is the default number of logs to batch before sending
+	BatchSize = 100
 	
-	// FlushInterval is the maximum time to wait before sending logs
+	// BatchBytes is the default uncompressed batch size at which logs are sent
+	BatchBytes = 1 << 20
+	
+	// FlushInterval is the default maximum time to wait before sending logs
 	FlushInterval = 5 * time.Second
+	
+	// MaxPayloadEntries is the most logs the intake accepts in one payload
+	MaxPayloadEntries = 1000
+	
+	// MaxPayloadBytes is the largest uncompressed payload the intake accepts
+	MaxPayloadBytes = 5 << 20
+	
+	// MaxLogBytes is the largest single log the intake accepts; larger logs
+	// have their message truncated before they are sent
+	MaxLogBytes = 1 << 20
+	
+	// truncatedSuffix marks a message that was truncated to fit MaxLogBytes
+	truncatedSuffix = "...TRUNCATED"
+	
+	// DefaultCloseTimeout is how long Close waits for in-flight sends by default
+	DefaultCloseTimeout = 3 * time.Second
 )
 
 // DatadogLogEntry represents a log entry in Datadog format
@@ -38,21 +59,45 @@ type DatadogLogEntry struct {
 	Environment string                 `json:"env,omitempty"`
 }
 
-// DatadogHandler is a slog.Handler that sends logs to Datadog
+// DatadogHandler is a slog.Handler that sends logs to Datadog, or to another
+// Sink. Handlers derived with WithAttrs and WithGroup share the pipeline of
+// the handler they were derived from and carry their own attributes and group
+// prefix.
 type DatadogHandler struct {
-	cfg          *config.Config
-	minLevel     slog.Level
-	fallback     slog.Handler
-	service      string
-	environment  string
-	hostname     string
+	pipeline    *logPipeline
+	minLevel    slog.Level
+	fallback    slog.Handler
+	service     string
+	environment string
+	hostname    string
+	attrs       map[string]interface{}
+	prefix      string
+}
+
+// logPipeline batches log entries, spools them and sends them to a Sink. It
+// is shared by a DatadogHandler and all handlers derived from it.
+type logPipeline struct {
+	out          Sink
 	logChan      chan DatadogLogEntry
 	wg           sync.WaitGroup
 	stopChan     chan struct{}
-	client       *http.Client
 	flushTicker  *time.Ticker
-	logBuffer    []DatadogLogEntry
+	logBuffer    []json.RawMessage
+	bufferBytes  int
 	bufferMutex  sync.Mutex
+	batchSize    int
+	batchBytes   int
+	spool        *spool
+	sends        sync.WaitGroup
+	closeTimeout time.Duration
+	timedOut     chan struct{}
+	sampler      sampler
+	limiter      *tokenBucket
+	counters     logCounters
+	inFlight     atomic.Int64
+	summary      func(LogStats) DatadogLogEntry
+	// fallback receives the pipeline's own errors
+	fallback slog.Handler
 }
 
 // DatadogHandlerOptions contains options for creating a DatadogHandler
@@ -68,6 +113,52 @@ type DatadogHandlerOptions struct {
 	
 	// Environment is the environment (e.g., prod, staging)
 	Environment string
+	
+	// BatchSize is the number of logs to batch before sending (default 100,
+	// at most MaxPayloadEntries)
+	BatchSize int
+	
+	// BatchBytes is the uncompressed batch size at which logs are sent
+	// (default 1 MiB, at most MaxPayloadBytes)
+	BatchBytes int
+	
+	// FlushInterval is the maximum time to wait before sending logs (default 5s)
+	FlushInterval time.Duration
+	
+	// Sink is where batches are sent. When nil, logs are sent to the Datadog
+	// logs intake for the configured site, if an API key is configured.
+	Sink Sink
+	
+	// DisableCompression sends payloads to the default Datadog sink uncompressed
+	// instead of gzipped
+	DisableCompression bool
+	
+	// Endpoint overrides the default Datadog sink's intake URL, e.g., to send
+	// through a proxy
+	Endpoint string
+	
+	// SpoolDir, when set, is a directory where batches are written before
+	// they are sent. Batches that fail to send stay on disk and are retried,
+	// with backoff, by later runs. Each sink spools to its own subdirectory.
+	SpoolDir string
+	
+	// SpoolMaxBytes caps the total size of the spool (default 10 MiB)
+	SpoolMaxBytes int64
+	
+	// SpoolMaxAge drops spooled batches older than this (default 18h)
+	SpoolMaxAge time.Duration
+	
+	// CloseTimeout bounds how long Close waits for in-flight sends (default 3s)
+	CloseTimeout time.Duration
+	
+	// SampleRates is the fraction of logs kept at each level, e.g.
+	// DefaultSampleRates; levels without a rate are always kept
+	SampleRates map[slog.Level]float64
+	
+	// RateLimit is the number of logs per second sent to Datadog, with bursts
+	// of up to RateBurst; zero means no limit. Errors are never rate limited.
+	RateLimit float64
+	RateBurst int
 }
 
 // NewDatadogHandler creates a new DatadogHandler
@@ -90,31 +181,83 @@ func NewDatadogHandler(cfg *config.Config, opts *DatadogHandlerOptions) *Datadog
 		opts.Service = "datadog-cli"
 	}
 	
+	// Default batching if not provided, within the intake limits
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = BatchSize
+	}
+	if opts.BatchSize > MaxPayloadEntries {
+		opts.BatchSize = MaxPayloadEntries
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = BatchBytes
+	}
+	if opts.BatchBytes > MaxPayloadBytes {
+		opts.BatchBytes = MaxPayloadBytes
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = FlushInterval
+	}
+	
+	// Default close timeout if not provided
+	if opts.CloseTimeout <= 0 {
+		opts.CloseTimeout = DefaultCloseTimeout
+	}
+	
 	// Get hostname
 	hostname, err := os.Hostname()
 	if err != nil {
 		hostname = "unknown"
 	}
 	
+	// Default to the Datadog logs intake
+	if opts.Sink == nil && cfg.APIKey != "" {
+		opts.Sink = NewDatadogSink(cfg, opts.Endpoint, !opts.DisableCompression)
+	}
+	
+	pipeline := &logPipeline{
+		out:          opts.Sink,
+		logChan:      make(chan DatadogLogEntry, 100),
+		stopChan:     make(chan struct{}),
+		flushTicker:  time.NewTicker(opts.FlushInterval),
+		logBuffer:    make([]json.RawMessage, 0, opts.BatchSize),
+		batchSize:    opts.BatchSize,
+		batchBytes:   opts.BatchBytes,
+		closeTimeout: opts.CloseTimeout,
+		timedOut:     make(chan struct{}),
+		sampler:      sampler{rates: opts.SampleRates},
+		limiter:      newTokenBucket(opts.RateLimit, opts.RateBurst),
+		fallback:     opts.Fallback,
+	}
+	
+	// Open the spool and retry batches left behind by earlier runs
+	if opts.SpoolDir != "" && opts.Sink != nil {
+		sp, err := newSpool(filepath.Join(opts.SpoolDir, opts.Sink.Name()), opts.SpoolMaxBytes, opts.SpoolMaxAge)
+		if err != nil {
+			pipeline.reportError("Error opening log spool, logs will not be spooled", err)
+		} else {
+			sp.report = pipeline.reportError
+			pipeline.spool = sp
+			sp.prune(time.Now())
+			if pending := sp.due(time.Now()); len(pending) > 0 {
+				pipeline.sends.Add(1)
+				go pipeline.replay(pending)
+			}
+		}
+	}
+	
+	// Start the background worker
+	pipeline.wg.Add(1)
+	go pipeline.processLogs()
+	
 	h := &DatadogHandler{
-		cfg:         cfg,
+		pipeline:    pipeline,
 		minLevel:    opts.MinLevel,
 		fallback:    opts.Fallback,
 		service:     opts.Service,
 		environment: opts.Environment,
 		hostname:    hostname,
-		logChan:     make(chan DatadogLogEntry, 100),
-		stopChan:    make(chan struct{}),
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
-		flushTicker: time.NewTicker(FlushInterval),
-		logBuffer:   make([]DatadogLogEntry, 0, BatchSize),
 	}
-	
-	// Start the background worker
-	h.wg.Add(1)
-	go h.processLogs()
+	pipeline.summary = h.summaryEntry
 	
 	return h
 }
@@ -136,6 +279,16 @@ func (h *DatadogHandler) Handle(ctx context.Context, record slog.Record) error {
 		return nil
 	}
 	
+	// Sample, then rate limit all but errors
+	if !h.pipeline.sampler.keep(record.Level) {
+		h.pipeline.counters.sampled.Add(1)
+		return nil
+	}
+	if record.Level < slog.LevelError && !h.pipeline.limiter.allow(time.Now()) {
+		h.pipeline.counters.rateLimited.Add(1)
+		return nil
+	}
+	
 	// Convert slog.Record to DatadogLogEntry
 	entry := DatadogLogEntry{
 		Message:     record.Message,
@@ -145,109 +298,209 @@ func (h *DatadogHandler) Handle(ctx context.Context, record slog.Record) error {
 		Hostname:    h.hostname,
 		Timestamp:   record.Time.UnixNano() / int64(time.Millisecond),
 		Environment: h.environment,
-		Attributes:  make(map[string]interface{}),
+		Attributes:  make(map[string]interface{}, len(h.attrs)+3),
+	}
+	
+	// Add attributes bound with WithAttrs
+	for key, value := range h.attrs {
+		entry.Attributes[key] = value
 	}
 	
-	// Add file and line information
-	if pc, file, line, ok := runtime.Caller(4); ok {
-		entry.Attributes["file"] = file
-		entry.Attributes["line"] = line
-		if fn := runtime.FuncForPC(pc); fn != nil {
-			entry.Attributes["function"] = fn.Name()
+	// Add file and line information of the logging call
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.Attributes["file"] = frame.File
+		entry.Attributes["line"] = frame.Line
+		if frame.Function != "" {
+			entry.Attributes["function"] = frame.Function
 		}
 	}
 	
-	// Add attributes from record
+	// Correlate with the trace of the command or API call being logged
+	if span := SpanFromContext(ctx); span != nil {
+		entry.Attributes["dd.trace_id"] = strconv.FormatUint(span.TraceID, 10)
+		entry.Attributes["dd.span_id"] = strconv.FormatUint(span.SpanID, 10)
+	}
+	
+	// Add attributes from record, within the handler's groups
 	record.Attrs(func(attr slog.Attr) bool {
-		addAttrToMap(entry.Attributes, "", attr)
+		addAttrToMap(entry.Attributes, h.prefix, attr)
 		return true
 	})
 	
 	// Send to channel for async processing
 	select {
-	case h.logChan <- entry:
+	case h.pipeline.logChan <- entry:
 		// Successfully queued
 	default:
-		// Channel full, log to fallback
-		return fmt.Errorf("datadog log channel full, dropping log")
+		// Channel full; the log already went to the fallback handler
+		h.pipeline.counters.dropped.Add(1)
 	}
 	
 	return nil
 }
 
-// WithAttrs implements slog.Handler.
+// WithAttrs implements slog.Handler. The attributes are added, within the
+// handler's groups, to every entry logged by the new handler.
 func (h *DatadogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	// Create a new handler with the same configuration
-	newHandler := &DatadogHandler{
-		cfg:         h.cfg,
-		minLevel:    h.minLevel,
-		fallback:    h.fallback.WithAttrs(attrs),
-		service:     h.service,
-		environment: h.environment,
-		hostname:    h.hostname,
-		logChan:     h.logChan,
-		stopChan:    h.stopChan,
-		client:      h.client,
-		flushTicker: h.flushTicker,
-		logBuffer:   h.logBuffer,
-		bufferMutex: h.bufferMutex,
+	if len(attrs) == 0 {
+		return h
+	}
+	
+	newHandler := h.clone()
+	newHandler.fallback = h.fallback.WithAttrs(attrs)
+	newHandler.attrs = make(map[string]interface{}, len(h.attrs)+len(attrs))
+	for key, value := range h.attrs {
+		newHandler.attrs[key] = value
+	}
+	for _, attr := range attrs {
+		addAttrToMap(newHandler.attrs, h.prefix, attr)
 	}
 	
 	return newHandler
 }
 
-// WithGroup implements slog.Handler.
+// WithGroup implements slog.Handler. Attributes added later, by WithAttrs or
+// by the record, are nested under the group as dotted keys.
 func (h *DatadogHandler) WithGroup(name string) slog.Handler {
-	// Create a new handler with the same configuration
-	newHandler := &DatadogHandler{
-		cfg:         h.cfg,
+	if name == "" {
+		return h
+	}
+	
+	newHandler := h.clone()
+	newHandler.fallback = h.fallback.WithGroup(name)
+	if h.prefix != "" {
+		newHandler.prefix = h.prefix + "." + name
+	} else {
+		newHandler.prefix = name
+	}
+	
+	return newHandler
+}
+
+// clone returns a copy of the handler sharing its pipeline and bound attributes
+func (h *DatadogHandler) clone() *DatadogHandler {
+	return &DatadogHandler{
+		pipeline:    h.pipeline,
 		minLevel:    h.minLevel,
-		fallback:    h.fallback.WithGroup(name),
+		fallback:    h.fallback,
 		service:     h.service,
 		environment: h.environment,
 		hostname:    h.hostname,
-		logChan:     h.logChan,
-		stopChan:    h.stopChan,
-		client:      h.client,
-		flushTicker: h.flushTicker,
-		logBuffer:   h.logBuffer,
-		bufferMutex: h.bufferMutex,
+		attrs:       h.attrs,
+		prefix:      h.prefix,
 	}
-	
-	return newHandler
 }
 
-// Close stops the background worker and flushes any remaining logs
+// Close stops the background worker, flushes any remaining logs and waits,
+// up to the close timeout, for in-flight sends to finish. Batches still
+// unsent when the timeout expires remain in the spool, if one is configured.
+// A summary of the handler's LogStats is sent with the last batch and, once
+// in-flight sends finish, logged to the fallback handler at debug level, or
+// at warning level if logs were dropped or could not be sent.
 func (h *DatadogHandler) Close() error {
+	err := h.pipeline.close()
+	
+	stats := h.Stats()
+	if stats.total() == 0 {
+		return err
+	}
+	level := slog.LevelDebug
+	if stats.Dropped > 0 || stats.Errors > 0 {
+		level = slog.LevelWarn
+	}
+	ctx := context.Background()
+	if h.fallback.Enabled(ctx, level) {
+		record := slog.NewRecord(time.Now(), level, logSummaryMessage, 0)
+		record.AddAttrs(stats.attrs()...)
+		h.fallback.Handle(ctx, record)
+	}
+	
+	return err
+}
+
+// Stats returns the counts of logs sent, sampled, rate limited and dropped
+// by the handler and all handlers derived from it
+func (h *DatadogHandler) Stats() LogStats {
+	return h.pipeline.counters.stats()
+}
+
+// summaryEntry builds the summary log sent to Datadog at Close
+func (h *DatadogHandler) summaryEntry(stats LogStats) DatadogLogEntry {
+	entry := DatadogLogEntry{
+		Message:     logSummaryMessage,
+		Status:      "info",
+		Service:     h.service,
+		DDSource:    "datadog-cli",
+		Hostname:    h.hostname,
+		Timestamp:   time.Now().UnixNano() / int64(time.Millisecond),
+		Environment: h.environment,
+		Attributes:  make(map[string]interface{}),
+	}
+	for _, attr := range stats.attrs() {
+		addAttrToMap(entry.Attributes, "", attr)
+	}
+	return entry
+}
+
+// close stops the worker and waits for in-flight sends
+func (h *logPipeline) close() error {
 	// Signal the worker to stop
 	close(h.stopChan)
 	
 	// Wait for the worker to finish
 	h.wg.Wait()
+	h.flushTicker.Stop()
 	
-	return nil
+	// Wait for in-flight sends
+	done := make(chan struct{})
+	go func() {
+		h.sends.Wait()
+		close(done)
+	}()
+	
+	select {
+	case <-done:
+		return nil
+	case <-time.After(h.closeTimeout):
+		close(h.timedOut)
+		if h.spool != nil {
+			return fmt.Errorf("timed out after %s sending logs to %s; unsent logs remain in %s", h.closeTimeout, h.out.Name(), h.spool.dir)
+		}
+		return fmt.Errorf("timed out after %s sending logs to %s", h.closeTimeout, h.out.Name())
+	}
 }
 
 // processLogs processes logs in the background
-func (h *DatadogHandler) processLogs() {
+func (h *logPipeline) processLogs() {
 	defer h.wg.Done()
 	
 	for {
 		select {
 		case <-h.stopChan:
-			// Flush any remaining logs
-			h.flush()
+			// Drain queued logs and flush them with the summary
+			h.bufferMutex.Lock()
+			for len(h.logChan) > 0 {
+				h.bufferLocked(<-h.logChan)
+			}
+			// Logs still being sent are reported as sent in the summary
+			stats := h.counters.stats()
+			stats.Sent += h.inFlight.Load() + int64(len(h.logBuffer))
+			if h.summary != nil && h.out != nil && stats.total() > 0 {
+				// The summary itself is not counted as sent
+				count := len(h.logBuffer)
+				h.bufferLocked(h.summary(stats))
+				h.flushCountLocked(count)
+			} else {
+				h.flushLocked()
+			}
+			h.bufferMutex.Unlock()
 			return
 			
 		case entry := <-h.logChan:
 			// Add to buffer
 			h.bufferMutex.Lock()
-			h.logBuffer = append(h.logBuffer, entry)
-			
-			// Flush if buffer is full
-			if len(h.logBuffer) >= BatchSize {
-				h.flushLocked()
-			}
+			h.bufferLocked(entry)
 			h.bufferMutex.Unlock()
 			
 		case <-h.flushTicker.C:
@@ -257,8 +510,78 @@ func (h *DatadogHandler) processLogs() {
 	}
 }
 
+// bufferLocked encodes an entry and adds it to the log buffer, flushing first
+// if the entry would push the batch past its byte limit and afterwards if the
+// batch is full (must be called with bufferMutex held)
+func (h *logPipeline) bufferLocked(entry DatadogLogEntry) {
+	data, err := encodeEntry(entry)
+	if err != nil {
+		h.counters.dropped.Add(1)
+		h.reportError("Error marshaling log", err)
+		return
+	}
+	
+	// Each entry adds its separator in the JSON array
+	size := len(data) + 1
+	if len(h.logBuffer) > 0 && h.bufferBytes+size > h.batchBytes {
+		h.flushLocked()
+	}
+	
+	h.logBuffer = append(h.logBuffer, data)
+	h.bufferBytes += size
+	
+	if len(h.logBuffer) >= h.batchSize || h.bufferBytes >= h.batchBytes {
+		h.flushLocked()
+	}
+}
+
+// encodeEntry marshals an entry, truncating its message so that the encoded
+// entry fits MaxLogBytes
+func encodeEntry(entry DatadogLogEntry) (json.RawMessage, error) {
+	data, err := json.Marshal(entry)
+	if err != nil || len(data) <= MaxLogBytes {
+		return data, err
+	}
+	
+	// Escaping can make the encoded message longer than the message itself,
+	// so shrink until the entry fits
+	excess := len(data) - MaxLogBytes + len(truncatedSuffix)
+	for excess > 0 {
+		keep := len(entry.Message) - excess
+		if keep < 0 {
+			keep = 0
+		}
+		entry.Message = truncateUTF8(entry.Message, keep) + truncatedSuffix
+		if data, err = json.Marshal(entry); err != nil {
+			return nil, err
+		}
+		if len(data) <= MaxLogBytes || keep == 0 {
+			break
+		}
+		excess = len(data) - MaxLogBytes
+	}
+	
+	// Attributes alone are too large; send the message without them
+	if len(data) > MaxLogBytes {
+		entry.Attributes = map[string]interface{}{"truncated": true}
+		return json.Marshal(entry)
+	}
+	return data, nil
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
 // flush flushes the log buffer
-func (h *DatadogHandler) flush() {
+func (h *logPipeline) flush() {
 	h.bufferMutex.Lock()
 	defer h.bufferMutex.Unlock()
 	
@@ -266,63 +589,132 @@ func (h *DatadogHandler) flush() {
 }
 
 // flushLocked flushes the log buffer (must be called with bufferMutex held)
-func (h *DatadogHandler) flushLocked() {
+func (h *logPipeline) flushLocked() {
+	h.flushCountLocked(len(h.logBuffer))
+}
+
+// flushCountLocked flushes the log buffer, of which count logs are counted in
+// LogStats (must be called with bufferMutex held)
+func (h *logPipeline) flushCountLocked(count int) {
 	if len(h.logBuffer) == 0 {
 		return
 	}
 	
-	// Copy buffer and reset
-	logs := make([]DatadogLogEntry, len(h.logBuffer))
-	copy(logs, h.logBuffer)
-	h.logBuffer = h.logBuffer[:0]
-	
-	// Send logs in a separate goroutine
-	go h.sendLogs(logs)
-}
-
-// sendLogs sends logs to Datadog
-func (h *DatadogHandler) sendLogs(logs []DatadogLogEntry) {
-	// Skip if no API key
-	if h.cfg.APIKey == "" {
+	// Skip if there is nowhere to send logs
+	if h.out == nil {
+		h.logBuffer = h.logBuffer[:0]
+		h.bufferBytes = 0
 		return
 	}
 	
-	// Construct URL
-	site := h.cfg.Site
-	if site == "" {
-		site = "datadoghq.com"
+	// Join the encoded logs into a JSON array
+	data := make([]byte, 0, h.bufferBytes+1)
+	data = append(data, '[')
+	for i, entry := range h.logBuffer {
+		if i > 0 {
+			data = append(data, ',')
+		}
+		data = append(data, entry...)
 	}
-	url := fmt.Sprintf(DatadogLogsEndpoint, site)
-	
-	// Marshal logs to JSON
-	data, err := json.Marshal(logs)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling logs: %v\n", err)
-		return
+	data = append(data, ']')
+	h.logBuffer = h.logBuffer[:0]
+	h.bufferBytes = 0
+	
+	// Write the batch to the spool before sending so it survives a crash
+	var path string
+	if h.spool != nil {
+		var err error
+		if path, err = h.spool.write(data); err != nil {
+			h.reportError("Error spooling logs", err)
+		} else if path, err = h.spool.claim(path); err != nil {
+			// Claimed by a concurrent run replaying the spool
+			return
+		}
 	}
 	
-	// Create request
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(data)))
+	// Send logs in a separate goroutine that Close waits for
+	h.sends.Add(1)
+	h.inFlight.Add(int64(count))
+	go func() {
+		defer h.sends.Done()
+		defer h.inFlight.Add(-int64(count))
+		h.sendBatch(data, path, count)
+	}()
+}
+
+// sendBatch sends a batch of count logs and settles its spool file, if any:
+// delivered and undeliverable batches are removed, batches that may succeed
+// later are kept. Delivered logs count as sent and logs that are not kept
+// count as dropped.
+func (h *logPipeline) sendBatch(data []byte, path string, count int) error {
+	err := h.out.Send(context.Background(), data)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
-		return
+		h.reportError("Error sending logs to "+h.out.Name(), err)
+	} else {
+		h.counters.sent.Add(int64(count))
 	}
 	
-	// Add headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("DD-API-KEY", h.cfg.APIKey)
-	
-	// Send request
-	resp, err := h.client.Do(req)
+	if path == "" {
+		if err != nil {
+			h.counters.dropped.Add(int64(count))
+		}
+		return err
+	}
+	if err != nil && isRetryable(err) {
+		if releaseErr := h.spool.release(path); releaseErr != nil {
+			h.reportError("Error returning logs to spool", releaseErr)
+		}
+		return err
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error sending logs to Datadog: %v\n", err)
+		h.counters.dropped.Add(int64(count))
+	}
+	h.spool.remove(path)
+	return err
+}
+
+// reportError counts a failure to spool or send logs and logs it to the
+// fallback handler at warning level, so it follows the local log settings
+// instead of writing to the terminal directly
+func (h *logPipeline) reportError(msg string, err error) {
+	h.counters.errors.Add(1)
+	ctx := context.Background()
+	if !h.fallback.Enabled(ctx, slog.LevelWarn) {
 		return
 	}
-	defer resp.Body.Close()
+	record := slog.NewRecord(time.Now(), slog.LevelWarn, msg, 0)
+	record.AddAttrs(slog.String("error", err.Error()))
+	h.fallback.Handle(ctx, record)
+}
+
+// replay sends batches left in the spool by earlier runs, oldest first. It
+// stops at the first failure that may succeed later, e.g. while offline, and
+// when Close gives up waiting.
+func (h *logPipeline) replay(pending []spoolFile) {
+	defer h.sends.Done()
 	
-	// Check response
-	if resp.StatusCode >= 300 {
-		fmt.Fprintf(os.Stderr, "Error from Datadog logs API: %s\n", resp.Status)
+	for _, f := range pending {
+		select {
+		case <-h.timedOut:
+			return
+		default:
+		}
+		
+		path, err := h.spool.claim(f.path)
+		if err != nil {
+			// Claimed by a concurrent run
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			h.spool.remove(path)
+			continue
+		}
+		var batch []json.RawMessage
+		json.Unmarshal(data, &batch)
+		if err := h.sendBatch(data, path, len(batch)); err != nil && isRetryable(err) {
+			return
+		}
 	}
 }
 
@@ -342,6 +734,18 @@ func levelToStatus(level slog.Level) string {
 
 // addAttrToMap adds a slog.Attr to a map
 func addAttrToMap(m map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	
+	// Groups with an empty key are inlined; other attrs with empty keys are ignored
+	if attr.Key == "" {
+		if attr.Value.Kind() == slog.KindGroup {
+			for _, a := range attr.Value.Group() {
+				addAttrToMap(m, prefix, a)
+			}
+		}
+		return
+	}
+	
 	key := attr.Key
 	if prefix != "" {
 		key = prefix + "." + key
@@ -356,9 +760,6 @@ func addAttrToMap(m map[string]interface{}, prefix string, attr slog.Attr) {
 	case slog.KindAny:
 		// For any values, use the value directly
 		m[key] = attr.Value.Any()
-	case slog.KindLogValuer:
-		// For LogValuer, resolve and add
-		m[key] = attr.Value.Resolve().Any()
 	default:
 		// For other kinds, use the value directly
 		m[key] = attr.Value.Any()
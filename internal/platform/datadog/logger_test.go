@@ -1,13 +1,18 @@
 package datadog
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/padawandba/datadog-cli/internal/platform/config"
 )
@@ -78,10 +83,17 @@ func TestDatadogHandler_Handle(t *testing.T) {
 			t.Errorf("Expected DD-API-KEY header to be test-api-key, got %s", r.Header.Get("DD-API-KEY"))
 		}
 
-		// Read request body
-		buf := make([]byte, r.ContentLength)
-		_, err := r.Body.Read(buf)
-		if err != nil && err.Error() != "EOF" {
+		// Read the gzipped request body
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("Expected Content-Encoding header to be gzip, got %s", r.Header.Get("Content-Encoding"))
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("Error reading request body: %v", err)
+			return
+		}
+		buf, err := io.ReadAll(gz)
+		if err != nil {
 			t.Errorf("Error reading request body: %v", err)
 		}
 		receivedLogs = buf
@@ -103,35 +115,27 @@ func TestDatadogHandler_Handle(t *testing.T) {
 		MinLevel:    slog.LevelDebug,
 		Service:     "test-service",
 		Environment: "test",
+		Endpoint:    server.URL,
 	})
 	
 	// Replace the client with one that points to our test server
-	h.client = &http.Client{
+	h.pipeline.out.(*DatadogSink).client = &http.Client{
 		Transport: &http.Transport{},
 		Timeout:   5 * time.Second,
 	}
 	
-	// Modify the flushInterval for faster testing
-	h.flushTicker.Reset(100 * time.Millisecond)
-	
-	defer h.Close()
-
 	// Log a test message
 	logger := slog.New(h)
 	logger.Info("Test message", "key1", "value1", "key2", 42)
 
-	// Wait for logs to be processed
-	time.Sleep(200 * time.Millisecond)
-
-	// Force a flush
-	h.flush()
-
-	// Wait for the request to complete
-	time.Sleep(200 * time.Millisecond)
+	// Close flushes the buffer and waits for the request to complete
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
 
 	// Check that logs were received
 	if len(receivedLogs) == 0 {
-		t.Skip("No logs received by test server - this test may be flaky and should be run manually")
+		t.Fatal("No logs received by test server")
 	}
 
 	// Check log content (basic validation)
@@ -201,4 +205,148 @@ func TestAddAttrToMap(t *testing.T) {
 	if len(m) != len(expectedKeys) {
 		t.Errorf("Expected map to have %d entries, got %d", len(expectedKeys), len(m))
 	}
-} 
\ No newline at end of file
+} 
+
+func TestEncodeEntry_Truncates(t *testing.T) {
+	entry := DatadogLogEntry{
+		Message: strings.Repeat("é", MaxLogBytes),
+		Status:  "info",
+	}
+
+	data, err := encodeEntry(entry)
+	if err != nil {
+		t.Fatalf("encodeEntry() error = %v", err)
+	}
+	if len(data) > MaxLogBytes {
+		t.Errorf("encodeEntry() = %d bytes, want at most %d", len(data), MaxLogBytes)
+	}
+
+	var got DatadogLogEntry
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("truncated entry is not valid JSON: %v", err)
+	}
+	if !strings.HasSuffix(got.Message, truncatedSuffix) || !utf8.ValidString(got.Message) {
+		t.Errorf("truncated message does not end in %q or splits a rune", truncatedSuffix)
+	}
+}
+
+func TestDatadogHandler_BatchesByBytes(t *testing.T) {
+	var mu sync.Mutex
+	var payloads [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		payloads = append(payloads, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: "test-api-key"}
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel:           slog.LevelInfo,
+		BatchBytes:         400,
+		DisableCompression: true,
+		Endpoint:           server.URL,
+	})
+
+	h.pipeline.bufferMutex.Lock()
+	for i := 0; i < 5; i++ {
+		h.pipeline.bufferLocked(DatadogLogEntry{Message: strings.Repeat("x", 150)})
+	}
+	h.pipeline.bufferMutex.Unlock()
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	total := 0
+	for _, payload := range payloads {
+		if len(payload) > 400 {
+			t.Errorf("payload is %d bytes, want at most 400", len(payload))
+		}
+		var entries []DatadogLogEntry
+		if err := json.Unmarshal(payload, &entries); err != nil {
+			t.Fatalf("payload is not a JSON array: %v", err)
+		}
+		total += len(withoutSummary(entries))
+	}
+	if total != 5 || len(payloads) < 3 {
+		t.Errorf("got %d logs in %d payloads, want 5 logs in at least 3", total, len(payloads))
+	}
+}
+
+func TestDatadogHandler_WithAttrsAndGroups(t *testing.T) {
+	var mu sync.Mutex
+	var entries []DatadogLogEntry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []DatadogLogEntry
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("payload is not a JSON array: %v", err)
+		}
+		mu.Lock()
+		entries = append(entries, withoutSummary(batch)...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: "test-api-key"}
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel:           slog.LevelInfo,
+		Fallback:           slog.NewTextHandler(io.Discard, nil),
+		DisableCompression: true,
+		Endpoint:           server.URL,
+	})
+
+	logger := slog.New(h).With("command", "hosts list")
+	logger.Info("plain", "count", 3)
+	logger.WithGroup("request").With("id", 7).WithGroup("http").Info("nested", "status", 200, slog.Group("", "inlined", true))
+	logger.WithGroup("empty").Info("no attrs in group")
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("intake received %d logs, want 3", len(entries))
+	}
+
+	tests := []struct {
+		entry int
+		want  map[string]interface{}
+		not   []string
+	}{
+		{0, map[string]interface{}{"command": "hosts list", "count": float64(3)}, nil},
+		{1, map[string]interface{}{
+			"command":              "hosts list",
+			"request.id":           float64(7),
+			"request.http.status":  float64(200),
+			"request.http.inlined": true,
+		}, []string{"id", "status", "request.http.id"}},
+		{2, map[string]interface{}{"command": "hosts list"}, []string{"empty"}},
+	}
+
+	for _, tt := range tests {
+		attrs := entries[tt.entry].Attributes
+		for key, want := range tt.want {
+			if got := attrs[key]; got != want {
+				t.Errorf("%q: attributes[%q] = %v, want %v", entries[tt.entry].Message, key, got, want)
+			}
+		}
+		for _, key := range tt.not {
+			if _, ok := attrs[key]; ok {
+				t.Errorf("%q: unexpected attribute %q", entries[tt.entry].Message, key)
+			}
+		}
+	}
+}
+
+// withoutSummary drops the summary log sent at Close
+func withoutSummary(entries []DatadogLogEntry) []DatadogLogEntry {
+	var kept []DatadogLogEntry
+	for _, entry := range entries {
+		if entry.Message != logSummaryMessage {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,303 @@
+package datadog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+// Metrics destinations, selected with --metrics
+const (
+	MetricsOff       = "off"
+	MetricsDogStatsD = "dogstatsd"
+	MetricsAPI       = "api"
+)
+
+// DefaultDogStatsDAddr is the DogStatsD address of a local Datadog Agent
+const DefaultDogStatsDAddr = "localhost:8125"
+
+const (
+	// DatadogSeriesEndpoint is the endpoint for submitting count and gauge series
+	DatadogSeriesEndpoint = "https://api.%s/api/v2/series"
+
+	// DatadogDistributionEndpoint is the endpoint for submitting distribution points
+	DatadogDistributionEndpoint = "https://api.%s/api/v1/distribution_points"
+)
+
+// metricsSender delivers metrics. Counts are summed and distributions keep
+// every value; DogStatsD sends them as they are recorded, the series API when
+// the sender is flushed.
+type metricsSender interface {
+	count(name string, value int64, tags []string)
+	distribution(name string, value float64, tags []string)
+	flush(ctx context.Context) error
+	close() error
+}
+
+// dogStatsDSender sends metrics to a DogStatsD server over UDP or a Unix
+// domain socket
+type dogStatsDSender struct {
+	conn net.Conn
+}
+
+// newDogStatsDSender connects to addr: host:port for UDP, or unix:///path for
+// a Unix domain socket. An empty addr uses DD_DOGSTATSD_URL, then
+// DD_AGENT_HOST and DD_DOGSTATSD_PORT, then DefaultDogStatsDAddr.
+func newDogStatsDSender(addr string) (*dogStatsDSender, error) {
+	if addr == "" {
+		addr = os.Getenv("DD_DOGSTATSD_URL")
+	}
+	if addr == "" {
+		if host := os.Getenv("DD_AGENT_HOST"); host != "" {
+			port := os.Getenv("DD_DOGSTATSD_PORT")
+			if port == "" {
+				port = "8125"
+			}
+			addr = net.JoinHostPort(host, port)
+		}
+	}
+	if addr == "" {
+		addr = DefaultDogStatsDAddr
+	}
+
+	network := "udp"
+	if path, ok := strings.CutPrefix(addr, "unix://"); ok {
+		network, addr = "unixgram", path
+	} else {
+		addr = strings.TrimPrefix(addr, "udp://")
+	}
+
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to DogStatsD at %s: %w", addr, err)
+	}
+	return &dogStatsDSender{conn: conn}, nil
+}
+
+// count implements metricsSender.
+func (s *dogStatsDSender) count(name string, value int64, tags []string) {
+	s.send(fmt.Sprintf("%s:%d|c", name, value), tags)
+}
+
+// distribution implements metricsSender.
+func (s *dogStatsDSender) distribution(name string, value float64, tags []string) {
+	s.send(fmt.Sprintf("%s:%g|d", name, value), tags)
+}
+
+// send writes one datagram. Metrics are best effort: a missing agent must not
+// slow down or fail the CLI, so write errors are ignored.
+func (s *dogStatsDSender) send(metric string, tags []string) {
+	if len(tags) > 0 {
+		metric += "|#" + strings.Join(tags, ",")
+	}
+	s.conn.Write([]byte(metric))
+}
+
+// flush implements metricsSender. Metrics were sent as they were recorded.
+func (s *dogStatsDSender) flush(context.Context) error {
+	return nil
+}
+
+// close implements metricsSender.
+func (s *dogStatsDSender) close() error {
+	return s.conn.Close()
+}
+
+// seriesSender aggregates metrics in memory and submits them to the Datadog
+// API when flushed
+type seriesSender struct {
+	cfg             *config.Config
+	seriesURL       string
+	distributionURL string
+	hostname        string
+	client          *http.Client
+
+	mu            sync.Mutex
+	counts        map[string]*seriesCount
+	distributions map[string]*seriesDistribution
+}
+
+// seriesCount is a count summed over a run
+type seriesCount struct {
+	name  string
+	tags  []string
+	value int64
+}
+
+// seriesDistribution collects the values of a distribution over a run
+type seriesDistribution struct {
+	name   string
+	tags   []string
+	values []float64
+}
+
+// newSeriesSender creates a sender for the API of the configured site
+func newSeriesSender(cfg *config.Config, hostname string) *seriesSender {
+	site := cfg.Site
+	if site == "" {
+		site = "datadoghq.com"
+	}
+	site = strings.TrimPrefix(site, "api.")
+
+	return &seriesSender{
+		cfg:             cfg,
+		seriesURL:       fmt.Sprintf(DatadogSeriesEndpoint, site),
+		distributionURL: fmt.Sprintf(DatadogDistributionEndpoint, site),
+		hostname:        hostname,
+		client:          &http.Client{Timeout: sinkTimeout},
+		counts:          make(map[string]*seriesCount),
+		distributions:   make(map[string]*seriesDistribution),
+	}
+}
+
+// metricKey identifies a metric by name and tags
+func metricKey(name string, tags []string) string {
+	return name + "|" + strings.Join(tags, ",")
+}
+
+// count implements metricsSender.
+func (s *seriesSender) count(name string, value int64, tags []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := metricKey(name, tags)
+	c, ok := s.counts[key]
+	if !ok {
+		c = &seriesCount{name: name, tags: tags}
+		s.counts[key] = c
+	}
+	c.value += value
+}
+
+// distribution implements metricsSender.
+func (s *seriesSender) distribution(name string, value float64, tags []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := metricKey(name, tags)
+	d, ok := s.distributions[key]
+	if !ok {
+		d = &seriesDistribution{name: name, tags: tags}
+		s.distributions[key] = d
+	}
+	d.values = append(d.values, value)
+}
+
+// Series API payloads, limited to the fields the CLI sets
+type (
+	seriesPayload struct {
+		Series []seriesMetric `json:"series"`
+	}
+
+	seriesMetric struct {
+		Metric    string           `json:"metric"`
+		Type      int              `json:"type"`
+		Points    []seriesPoint    `json:"points"`
+		Tags      []string         `json:"tags,omitempty"`
+		Resources []seriesResource `json:"resources,omitempty"`
+	}
+
+	seriesPoint struct {
+		Timestamp int64   `json:"timestamp"`
+		Value     float64 `json:"value"`
+	}
+
+	seriesResource struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+
+	distributionPayload struct {
+		Series []distributionSeries `json:"series"`
+	}
+
+	distributionSeries struct {
+		Metric string          `json:"metric"`
+		Points [][]interface{} `json:"points"`
+		Tags   []string        `json:"tags,omitempty"`
+		Host   string          `json:"host,omitempty"`
+	}
+)
+
+// seriesTypeCount is the series API type of a count
+const seriesTypeCount = 1
+
+// flush implements metricsSender. Counts and distributions recorded since
+// the last flush are submitted with the current time.
+func (s *seriesSender) flush(ctx context.Context) error {
+	s.mu.Lock()
+	counts, distributions := s.counts, s.distributions
+	s.counts = make(map[string]*seriesCount)
+	s.distributions = make(map[string]*seriesDistribution)
+	s.mu.Unlock()
+
+	now := time.Now().Unix()
+	headers := map[string]string{"DD-API-KEY": s.cfg.APIKey}
+
+	if len(counts) > 0 {
+		payload := seriesPayload{}
+		for _, key := range sortedKeys(counts) {
+			c := counts[key]
+			payload.Series = append(payload.Series, seriesMetric{
+				Metric:    c.name,
+				Type:      seriesTypeCount,
+				Points:    []seriesPoint{{Timestamp: now, Value: float64(c.value)}},
+				Tags:      c.tags,
+				Resources: []seriesResource{{Name: s.hostname, Type: "host"}},
+			})
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if err := postJSON(ctx, s.client, "Datadog series API", s.seriesURL, data, headers, true); err != nil {
+			return err
+		}
+	}
+
+	if len(distributions) > 0 {
+		payload := distributionPayload{}
+		for _, key := range sortedKeys(distributions) {
+			d := distributions[key]
+			payload.Series = append(payload.Series, distributionSeries{
+				Metric: d.name,
+				Points: [][]interface{}{{now, d.values}},
+				Tags:   d.tags,
+				Host:   s.hostname,
+			})
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if err := postJSON(ctx, s.client, "Datadog distribution API", s.distributionURL, data, headers, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// close implements metricsSender.
+func (s *seriesSender) close() error {
+	return nil
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,239 @@
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultOTLPEndpoint is the OTLP/HTTP logs endpoint of a local collector
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/logs"
+
+// OTLPSink sends batches to an OTLP/HTTP logs endpoint, such as an
+// OpenTelemetry Collector, using the JSON encoding
+type OTLPSink struct {
+	client   *http.Client
+	endpoint string
+	headers  map[string]string
+}
+
+// NewOTLPSink creates a sink for the OTLP/HTTP logs endpoint. An empty
+// endpoint uses OTEL_EXPORTER_OTLP_LOGS_ENDPOINT, then
+// OTEL_EXPORTER_OTLP_ENDPOINT with /v1/logs appended, then DefaultOTLPEndpoint.
+// headers are added to every request, e.g., for authentication.
+func NewOTLPSink(endpoint string, headers map[string]string) *OTLPSink {
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT")
+	}
+	if endpoint == "" {
+		if base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); base != "" {
+			endpoint = strings.TrimSuffix(base, "/") + "/v1/logs"
+		}
+	}
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+
+	return &OTLPSink{
+		client:   &http.Client{Timeout: sinkTimeout},
+		endpoint: endpoint,
+		headers:  headers,
+	}
+}
+
+// Name implements Sink.
+func (s *OTLPSink) Name() string {
+	return SinkOTLP
+}
+
+// Send implements Sink. The batch is converted to an OTLP
+// ExportLogsServiceRequest.
+func (s *OTLPSink) Send(ctx context.Context, batch []byte) error {
+	payload, err := otlpPayload(batch)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.client, "OTLP endpoint", s.endpoint, payload, s.headers, true)
+}
+
+// otlpHeadersFromEnv parses OTEL_EXPORTER_OTLP_HEADERS (and the logs-specific
+// OTEL_EXPORTER_OTLP_LOGS_HEADERS), a comma-separated list of key=value pairs
+// with URL-encoded values
+func otlpHeadersFromEnv() map[string]string {
+	headers := make(map[string]string)
+	for _, name := range []string{"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_LOGS_HEADERS"} {
+		for _, pair := range strings.Split(os.Getenv(name), ",") {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				continue
+			}
+			if decoded, err := url.QueryUnescape(strings.TrimSpace(value)); err == nil {
+				value = decoded
+			}
+			headers[strings.TrimSpace(key)] = value
+		}
+	}
+	return headers
+}
+
+// OTLP JSON encoding of ExportLogsServiceRequest, limited to the fields the
+// CLI sets. 64-bit integers are encoded as strings, as the encoding requires.
+type (
+	otlpRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpLogRecord struct {
+		TimeUnixNano   string         `json:"timeUnixNano"`
+		SeverityNumber int            `json:"severityNumber"`
+		SeverityText   string         `json:"severityText"`
+		Body           otlpAnyValue   `json:"body"`
+		Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// otlpSeverities maps Datadog statuses to OTLP severity numbers and texts
+var otlpSeverities = map[string]struct {
+	number int
+	text   string
+}{
+	"debug":   {5, "DEBUG"},
+	"info":    {9, "INFO"},
+	"warning": {13, "WARN"},
+	"error":   {17, "ERROR"},
+}
+
+// otlpPayload converts a batch to an OTLP request, with one resource per
+// service, host and environment
+func otlpPayload(batch []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(batch))
+	decoder.UseNumber()
+
+	var entries []DatadogLogEntry
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidBatch, err)
+	}
+
+	var request otlpRequest
+	resources := make(map[[3]string]int)
+	for _, entry := range entries {
+		key := [3]string{entry.Service, entry.Hostname, entry.Environment}
+		i, ok := resources[key]
+		if !ok {
+			i = len(request.ResourceLogs)
+			resources[key] = i
+			request.ResourceLogs = append(request.ResourceLogs, otlpResourceLogs{
+				Resource: otlpResource{Attributes: otlpResourceAttributes(entry)},
+				ScopeLogs: []otlpScopeLogs{{
+					Scope: otlpScope{Name: entry.DDSource},
+				}},
+			})
+		}
+
+		severity := otlpSeverities[entry.Status]
+		record := otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(entry.Timestamp*1e6, 10),
+			SeverityNumber: severity.number,
+			SeverityText:   severity.text,
+			Body:           otlpValue(entry.Message),
+			Attributes:     otlpAttributes(entry.Attributes),
+		}
+		scope := &request.ResourceLogs[i].ScopeLogs[0]
+		scope.LogRecords = append(scope.LogRecords, record)
+	}
+
+	return json.Marshal(request)
+}
+
+// otlpResourceAttributes maps an entry's service, host and environment to
+// OpenTelemetry semantic convention attributes
+func otlpResourceAttributes(entry DatadogLogEntry) []otlpKeyValue {
+	attrs := []otlpKeyValue{
+		{Key: "service.name", Value: otlpValue(entry.Service)},
+		{Key: "host.name", Value: otlpValue(entry.Hostname)},
+	}
+	if entry.Environment != "" {
+		attrs = append(attrs, otlpKeyValue{Key: "deployment.environment", Value: otlpValue(entry.Environment)})
+	}
+	return attrs
+}
+
+// otlpAttributes converts entry attributes, sorted by key
+func otlpAttributes(attributes map[string]interface{}) []otlpKeyValue {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, otlpKeyValue{Key: key, Value: otlpValue(attributes[key])})
+	}
+	return attrs
+}
+
+// otlpValue converts a decoded JSON value. Numbers become ints when they are
+// integral; arrays and objects are kept as JSON strings.
+func otlpValue(v interface{}) otlpAnyValue {
+	switch v := v.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			s := v.String()
+			return otlpAnyValue{IntValue: &s}
+		}
+		if f, err := v.Float64(); err == nil {
+			return otlpAnyValue{DoubleValue: &f}
+		}
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	case nil:
+		s := ""
+		return otlpAnyValue{StringValue: &s}
+	default:
+		data, _ := json.Marshal(v)
+		s := string(data)
+		return otlpAnyValue{StringValue: &s}
+	}
+}
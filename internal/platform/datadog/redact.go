@@ -0,0 +1,342 @@
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+// Redacted replaces secrets in log messages and attributes
+const Redacted = "[REDACTED]"
+
+// minSecretLength keeps short configured values, which are not real keys,
+// from redacting unrelated text
+const minSecretLength = 8
+
+var (
+	// keyArgPattern matches a --dd-*-key flag with its value in one argument
+	keyArgPattern = regexp.MustCompile(`(?i)(--?dd-[a-z0-9-]*key=)(\S+)`)
+	
+	// keyArgFlagPattern matches a --dd-*-key flag whose value is the next argument
+	keyArgFlagPattern = regexp.MustCompile(`(?i)^--?dd-[a-z0-9-]*key$`)
+	
+	// keyArgSpacePattern matches a --dd-*-key flag and its value in a command line
+	keyArgSpacePattern = regexp.MustCompile(`(?i)(--?dd-[a-z0-9-]*key\s+)([^\s-]\S*)`)
+	
+	// queryKeyPattern matches key-like URL query parameters
+	queryKeyPattern = regexp.MustCompile(`(?i)\b((?:dd_)?(?:api|app|application)_?key=)([^&\s"']+)`)
+	
+	// headerPattern matches Authorization-like headers written as text
+	headerPattern = regexp.MustCompile(`(?i)\b((?:proxy-)?authorization|dd-api-key|dd-application-key|x-api-key|cookie)(["']?\s*[:=]\s*["']?)((?:bearer|basic|token)\s+)?([^\s,;"']+)`)
+)
+
+// defaultRedactKeys are attribute keys whose values are always redacted,
+// compared case-insensitively and ignoring '-' and '_'
+var defaultRedactKeys = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+	"dd-api-key",
+	"dd-app-key",
+	"dd-application-key",
+	"x-api-key",
+	"api_key",
+	"app_key",
+	"application_key",
+	"password",
+	"secret",
+	"token",
+}
+
+// Redactor masks secrets in log messages and attributes: the configured API
+// and application keys, --dd-*-key arguments, key-like URL query parameters,
+// Authorization-like headers, and the patterns and attribute keys listed in
+// the configuration file.
+type Redactor struct {
+	cfg      *config.Config
+	patterns []*regexp.Regexp
+	keys     map[string]bool
+}
+
+// NewRedactor creates a Redactor for the configuration. The API and
+// application keys are read from cfg when a log is written, so keys set by
+// flags after the Redactor is created are redacted too.
+func NewRedactor(cfg *config.Config) (*Redactor, error) {
+	r := &Redactor{
+		cfg:  cfg,
+		keys: make(map[string]bool),
+	}
+	
+	for _, key := range defaultRedactKeys {
+		r.keys[normalizeKey(key)] = true
+	}
+	for _, key := range cfg.RedactKeys {
+		r.keys[normalizeKey(key)] = true
+	}
+	
+	for _, pattern := range cfg.RedactPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	
+	return r, nil
+}
+
+// String redacts secrets in a string
+func (r *Redactor) String(s string) string {
+	if s == "" {
+		return s
+	}
+	
+	for _, secret := range []string{r.cfg.APIKey, r.cfg.AppKey} {
+		if len(secret) >= minSecretLength {
+			s = strings.ReplaceAll(s, secret, Redacted)
+		}
+	}
+	
+	s = keyArgPattern.ReplaceAllString(s, "${1}"+Redacted)
+	s = keyArgSpacePattern.ReplaceAllString(s, "${1}"+Redacted)
+	s = queryKeyPattern.ReplaceAllString(s, "${1}"+Redacted)
+	s = headerPattern.ReplaceAllString(s, "${1}${2}${3}"+Redacted)
+	
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// Body redacts an HTTP body. JSON bodies are decoded so that the values of
+// secret keys are redacted wherever they are nested, and are returned
+// compacted with object keys sorted; other bodies are redacted as text.
+func (r *Redactor) Body(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return []byte(r.String(string(body)))
+	}
+	
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r.value(v)); err != nil {
+		return []byte(r.String(string(body)))
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// Attr redacts an attribute, recursing into groups
+func (r *Redactor) Attr(attr slog.Attr) slog.Attr {
+	if r.keys[normalizeKey(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+	
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, r.String(value.String()))
+	
+	case slog.KindGroup:
+		group := value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = r.Attr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	
+	case slog.KindAny:
+		return slog.Any(attr.Key, r.value(value.Any()))
+	
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
+
+// value redacts the values that can carry secrets: strings, arguments,
+// headers, URLs, errors and maps. Structs, pointers and other types are
+// redacted through their JSON form; numbers and booleans are returned
+// unchanged.
+func (r *Redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return r.String(v)
+	
+	case []string:
+		return r.args(v)
+	
+	case http.Header:
+		return r.header(v)
+	
+	case map[string][]string:
+		return r.header(http.Header(v))
+	
+	case map[string]string:
+		redacted := make(map[string]string, len(v))
+		for key, val := range v {
+			if r.keys[normalizeKey(key)] {
+				redacted[key] = Redacted
+			} else {
+				redacted[key] = r.String(val)
+			}
+		}
+		return redacted
+	
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, val := range v {
+			redacted[i] = r.value(val)
+		}
+		return redacted
+	
+	case json.Number:
+		return v
+	
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			if r.keys[normalizeKey(key)] {
+				redacted[key] = Redacted
+			} else {
+				redacted[key] = r.value(val)
+			}
+		}
+		return redacted
+	
+	case *url.URL:
+		if v == nil {
+			return v
+		}
+		return r.String(v.String())
+	
+	case error:
+		return r.String(v.Error())
+	
+	case fmt.Stringer:
+		return r.String(v.String())
+	
+	default:
+		return r.structured(v)
+	}
+}
+
+// structured redacts a value of any other type by way of its JSON form, so
+// the secret fields of structs, pointers and typed maps are redacted like
+// those of a decoded JSON body
+func (r *Redactor) structured(v interface{}) interface{} {
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Invalid, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v
+	}
+	
+	// A value that cannot be encoded cannot be inspected either
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Redacted
+	}
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return r.String(string(data))
+	}
+	return r.value(generic)
+}
+
+// args redacts a list of command-line arguments, including the value that
+// follows a separate --dd-*-key flag
+func (r *Redactor) args(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if i > 0 && keyArgFlagPattern.MatchString(args[i-1]) {
+			redacted[i] = Redacted
+			continue
+		}
+		redacted[i] = r.String(arg)
+	}
+	return redacted
+}
+
+// header redacts HTTP headers
+func (r *Redactor) header(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for key, values := range h {
+		if r.keys[normalizeKey(key)] {
+			redacted[key] = []string{Redacted}
+			continue
+		}
+		redacted[key] = r.args(values)
+	}
+	return redacted
+}
+
+// normalizeKey lowercases a key and drops '-' and '_' so that DD-API-KEY,
+// dd_api_key and ddApiKey compare equal
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "-", "")
+	return strings.ReplaceAll(key, "_", "")
+}
+
+// RedactingHandler is a slog.Handler that redacts secrets in each record
+// before passing it to the next handler
+type RedactingHandler struct {
+	next     slog.Handler
+	redactor *Redactor
+}
+
+// NewRedactingHandler creates a RedactingHandler that passes redacted records to next
+func NewRedactingHandler(next slog.Handler, redactor *Redactor) *RedactingHandler {
+	return &RedactingHandler{
+		next:     next,
+		redactor: redactor,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.String(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.Attr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactor.Attr(attr)
+	}
+	return NewRedactingHandler(h.next.WithAttrs(redacted), h.redactor)
+}
+
+// WithGroup implements slog.Handler.
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return NewRedactingHandler(h.next.WithGroup(name), h.redactor)
+}
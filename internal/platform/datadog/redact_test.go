@@ -0,0 +1,229 @@
+package datadog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+const (
+	testAPIKey = "0123456789abcdef0123456789abcdef"
+	testAppKey = "fedcba9876543210fedcba9876543210fedcba98"
+)
+
+func TestRedactor_String(t *testing.T) {
+	cfg := &config.Config{
+		APIKey:         testAPIKey,
+		AppKey:         testAppKey,
+		RedactPatterns: []string{`acct-\d+`},
+	}
+	r, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"configured key", "key is " + testAPIKey, "key is [REDACTED]"},
+		{"key flag", "dd --dd-api-key=abc123 hosts list", "dd --dd-api-key=[REDACTED] hosts list"},
+		{"key flag with space", "dd --dd-app-key abc123 hosts list", "dd --dd-app-key [REDACTED] hosts list"},
+		{"query parameter", "https://api.datadoghq.com/api/v1/validate?api_key=abc123&x=1", "https://api.datadoghq.com/api/v1/validate?api_key=[REDACTED]&x=1"},
+		{"authorization header", "Authorization: Bearer abc123", "Authorization: Bearer [REDACTED]"},
+		{"api key header", `{"DD-API-KEY": "abc123"}`, `{"DD-API-KEY": "[REDACTED]"}`},
+		{"user pattern", "billing acct-42 failed", "billing [REDACTED] failed"},
+		{"nothing to redact", "hosts list returned 3 hosts", "hosts list returned 3 hosts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.String(tt.in); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Attr(t *testing.T) {
+	cfg := &config.Config{RedactKeys: []string{"customer_email"}}
+	r, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := r.Attr(slog.Any("args", []string{"dd", "--dd-api-key", "abc123", "hosts"})).Value.Any().([]string)
+	if args[2] != Redacted || args[3] != "hosts" {
+		t.Errorf("args = %v, want the flag value redacted", args)
+	}
+
+	header := r.Attr(slog.Any("headers", http.Header{"Dd-Api-Key": {"abc123"}, "Accept": {"application/json"}})).Value.Any().(http.Header)
+	if header.Get("Dd-Api-Key") != Redacted || header.Get("Accept") != "application/json" {
+		t.Errorf("headers = %v, want DD-API-KEY redacted", header)
+	}
+
+	group := r.Attr(slog.Group("user", slog.String("customer_email", "a@example.com"), slog.String("name", "a")))
+	for _, a := range group.Value.Group() {
+		if a.Key == "customer_email" && a.Value.String() != Redacted {
+			t.Errorf("configured key was not redacted in group: %v", a)
+		}
+	}
+}
+
+func TestRedactor_Body(t *testing.T) {
+	r, err := NewRedactor(&config.Config{APIKey: testAPIKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "nested secret keys",
+			body: `{"id": 12345678901234567, "options": {"password": "hunter2"}, "hooks": [{"token": "t0k3n", "url": "https://a.example/<hook>"}]}`,
+			want: `{"hooks":[{"token":"[REDACTED]","url":"https://a.example/<hook>"}],"id":12345678901234567,"options":{"password":"[REDACTED]"}}`,
+		},
+		{
+			name: "configured key in a value",
+			body: `{"message": "key ` + testAPIKey + `"}`,
+			want: `{"message":"key [REDACTED]"}`,
+		},
+		{
+			name: "not JSON",
+			body: "api_key=" + testAPIKey,
+			want: "api_key=[REDACTED]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(r.Body([]byte(tt.body))); got != tt.want {
+				t.Errorf("Body() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRedactor_InvalidPattern(t *testing.T) {
+	if _, err := NewRedactor(&config.Config{RedactPatterns: []string{"("}}); err == nil {
+		t.Error("NewRedactor() with an invalid pattern should fail")
+	}
+}
+
+// TestRedactingHandler_NeverSendsKeys checks that keys never reach either the
+// fallback output or the intake payload
+func TestRedactingHandler_NeverSendsKeys(t *testing.T) {
+	var mu sync.Mutex
+	var payload bytes.Buffer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		io.Copy(&payload, r.Body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: testAPIKey, AppKey: testAppKey}
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fallback bytes.Buffer
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel:           slog.LevelDebug,
+		Fallback:           slog.NewJSONHandler(&fallback, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		DisableCompression: true,
+		Endpoint:           server.URL,
+	})
+
+	logger := slog.New(NewRedactingHandler(h, redactor))
+	logger.Info("Executing command", "args", []string{"dd", "--dd-api-key=" + testAPIKey, "--dd-app-key", testAppKey})
+	logger.Debug("Datadog API request", "url", "https://api.datadoghq.com/api/v1/hosts?api_key="+testAPIKey)
+	logger.Error("Application error", "error", errors.New("request with key "+testAppKey+" failed"))
+	logger.With("headers", http.Header{"Dd-Application-Key": {testAppKey}}).Warn("Retrying")
+	logger.WithGroup("request").Info("Authorization: Bearer "+testAPIKey, "body", `{"api_key":"`+testAPIKey+`"}`)
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if payload.Len() == 0 {
+		t.Fatal("No logs received by test server")
+	}
+	for name, out := range map[string]string{"fallback": fallback.String(), "intake": payload.String()} {
+		for _, key := range []string{testAPIKey, testAppKey} {
+			if strings.Contains(out, key) {
+				t.Errorf("%s output contains a key: %s", name, out)
+			}
+		}
+		if !strings.Contains(out, Redacted) {
+			t.Errorf("%s output does not mark redactions: %s", name, out)
+		}
+	}
+}
+
+func TestRedactingHandler_RedactsStructs(t *testing.T) {
+	var mu sync.Mutex
+	var payload bytes.Buffer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		io.Copy(&payload, r.Body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: testAPIKey, AppKey: testAppKey, Site: "datadoghq.eu"}
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel:           slog.LevelDebug,
+		Fallback:           slog.NewTextHandler(io.Discard, nil),
+		DisableCompression: true,
+		Endpoint:           server.URL,
+	})
+
+	// A secret that is not one of the configured keys is found by its field name
+	const otherKey = "another-service-key-0123456789"
+	type requestBody struct {
+		Name   string
+		APIKey string
+		Retry  int
+	}
+
+	logger := slog.New(NewRedactingHandler(h, redactor))
+	logger.Info("Loaded config", "config", cfg)
+	logger.Info("Sending request", "body", requestBody{Name: "checkout", APIKey: otherKey, Retry: 3})
+	logger.Info("Sending request", "body", &requestBody{Name: "checkout", APIKey: otherKey})
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out := payload.String()
+	for _, key := range []string{testAPIKey, testAppKey, otherKey} {
+		if strings.Contains(out, key) {
+			t.Errorf("intake payload contains a key: %s", out)
+		}
+	}
+	for _, want := range []string{`"dd_site":"datadoghq.eu"`, `"Name":"checkout"`, `"Retry":3`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("intake payload missing %s: %s", want, out)
+		}
+	}
+}
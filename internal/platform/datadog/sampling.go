@@ -0,0 +1,158 @@
+package datadog
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is the default number of logs per second the CLI sends
+	// to Datadog, with bursts of up to DefaultRateBurst
+	DefaultRateLimit = 50
+	DefaultRateBurst = 100
+)
+
+// DefaultSampleRates keeps 10% of debug logs and all other logs
+var DefaultSampleRates = map[slog.Level]float64{
+	slog.LevelDebug: 0.1,
+}
+
+// LogStats counts what happened to the logs a DatadogHandler was given
+type LogStats struct {
+	// Sent is the number of logs delivered to the sink
+	Sent int64
+	
+	// Sampled is the number of logs left out by sampling
+	Sampled int64
+	
+	// RateLimited is the number of logs left out by the rate limit
+	RateLimited int64
+	
+	// Dropped is the number of logs lost because the queue was full or the
+	// intake could not be reached and no spool was configured
+	Dropped int64
+	
+	// Errors is the number of failed attempts to spool or send logs. Each
+	// failed send counts once, however many logs its batch held.
+	Errors int64
+}
+
+// logSummaryMessage is the message of the summary logged at Close
+const logSummaryMessage = "Datadog log handler summary"
+
+// total returns the number of logs and errors counted
+func (s LogStats) total() int64 {
+	return s.Sent + s.Sampled + s.RateLimited + s.Dropped + s.Errors
+}
+
+// attrs returns the stats as log attributes
+func (s LogStats) attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int64("logs.sent", s.Sent),
+		slog.Int64("logs.sampled", s.Sampled),
+		slog.Int64("logs.rate_limited", s.RateLimited),
+		slog.Int64("logs.dropped", s.Dropped),
+		slog.Int64("logs.errors", s.Errors),
+	}
+}
+
+// logCounters are the atomic counters behind LogStats
+type logCounters struct {
+	sent        atomic.Int64
+	sampled     atomic.Int64
+	rateLimited atomic.Int64
+	dropped     atomic.Int64
+	errors      atomic.Int64
+}
+
+// stats returns a snapshot of the counters
+func (c *logCounters) stats() LogStats {
+	return LogStats{
+		Sent:        c.sent.Load(),
+		Sampled:     c.sampled.Load(),
+		RateLimited: c.rateLimited.Load(),
+		Dropped:     c.dropped.Load(),
+		Errors:      c.errors.Load(),
+	}
+}
+
+// sampler keeps a configured fraction of the logs at each level
+type sampler struct {
+	rates map[slog.Level]float64
+}
+
+// keep reports whether a log at the given level is kept. Levels between the
+// standard ones use the rate of the standard level below them; levels without
+// a rate are always kept.
+func (s sampler) keep(level slog.Level) bool {
+	rate, ok := s.rates[standardLevel(level)]
+	if !ok || rate >= 1 {
+		return true
+	}
+	return rate > 0 && rand.Float64() < rate
+}
+
+// standardLevel rounds a level down to Debug, Info, Warn or Error
+func standardLevel(level slog.Level) slog.Level {
+	switch {
+	case level >= slog.LevelError:
+		return slog.LevelError
+	case level >= slog.LevelWarn:
+		return slog.LevelWarn
+	case level >= slog.LevelInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// tokenBucket is a token-bucket rate limiter. A nil bucket allows everything.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a bucket refilled at rate tokens per second, holding
+// at most burst tokens. It returns nil, no limit, if rate is not positive.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// allow takes a token if one is available
+func (b *tokenBucket) allow(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,160 @@
+package datadog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+func TestSampler_Keep(t *testing.T) {
+	s := sampler{rates: map[slog.Level]float64{
+		slog.LevelDebug: 0,
+		slog.LevelInfo:  1,
+	}}
+
+	tests := []struct {
+		level slog.Level
+		want  bool
+	}{
+		{slog.LevelDebug, false},
+		{slog.LevelDebug + 2, false}, // rounds down to debug
+		{slog.LevelInfo, true},
+		{slog.LevelError, true}, // no rate configured
+	}
+
+	for _, tt := range tests {
+		if got := s.keep(tt.level); got != tt.want {
+			t.Errorf("keep(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	b := newTokenBucket(2, 3)
+	now := b.last
+
+	for i := 0; i < 3; i++ {
+		if !b.allow(now) {
+			t.Fatalf("allow() #%d = false within the burst", i+1)
+		}
+	}
+	if b.allow(now) {
+		t.Error("allow() = true with the bucket empty")
+	}
+	if !b.allow(now.Add(500 * time.Millisecond)) {
+		t.Error("allow() = false after the bucket refilled one token")
+	}
+
+	if nolimit := newTokenBucket(0, 0); !nolimit.allow(now) {
+		t.Error("allow() on a bucket without a rate = false, want true")
+	}
+}
+
+func TestDatadogHandler_StatsAndSummary(t *testing.T) {
+	var mu sync.Mutex
+	var entries []DatadogLogEntry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []DatadogLogEntry
+		json.NewDecoder(r.Body).Decode(&batch)
+		mu.Lock()
+		entries = append(entries, batch...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: "test-api-key"}
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel:           slog.LevelDebug,
+		Fallback:           slog.NewTextHandler(io.Discard, nil),
+		DisableCompression: true,
+		Endpoint:           server.URL,
+		SampleRates:        map[slog.Level]float64{slog.LevelDebug: 0},
+		RateLimit:          0.001,
+		RateBurst:          2,
+	})
+
+	logger := slog.New(h)
+	logger.Debug("sampled out")
+	for i := 0; i < 5; i++ {
+		logger.Info("bulk operation")
+	}
+	logger.Error("errors are not rate limited")
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := LogStats{Sent: 3, Sampled: 1, RateLimited: 3}
+	if got := h.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	if len(entries) != 4 {
+		t.Fatalf("intake received %d logs, want 3 and the summary", len(entries))
+	}
+	summary := entries[3]
+	if summary.Message != logSummaryMessage {
+		t.Fatalf("last log = %q, want the summary", summary.Message)
+	}
+	for key, want := range map[string]float64{"logs.sent": 3, "logs.sampled": 1, "logs.rate_limited": 3, "logs.dropped": 0} {
+		if got := summary.Attributes[key]; got != want {
+			t.Errorf("summary %s = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestDatadogHandler_StatsFailedSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var local bytes.Buffer
+	cfg := &config.Config{APIKey: "test-api-key"}
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel:           slog.LevelInfo,
+		Fallback:           slog.NewTextHandler(&syncWriter{w: &local}, nil),
+		DisableCompression: true,
+		Endpoint:           server.URL,
+	})
+
+	logger := slog.New(h)
+	for i := 0; i < 3; i++ {
+		logger.Info("rejected")
+	}
+	h.Close()
+
+	// Rejected logs count as dropped only, not also as sent, and the one
+	// failed send counts as an error
+	want := LogStats{Dropped: 3, Errors: 1}
+	if got := h.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// The failure is logged to the fallback handler, not written to stderr
+	if out := local.String(); !strings.Contains(out, `level=WARN msg="Error sending logs to datadog"`) {
+		t.Errorf("fallback output missing the send error:\n%s", out)
+	}
+}
+
+// syncWriter serializes writes from the handler and its background sends
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
@@ -0,0 +1,236 @@
+package datadog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+// Log sink names, selected with --log-sink
+const (
+	SinkDatadog = "datadog"
+	SinkOTLP    = "otlp"
+	SinkFile    = "file"
+)
+
+// sinkTimeout bounds a single send by the HTTP sinks
+const sinkTimeout = 5 * time.Second
+
+// errInvalidBatch is returned for a batch that can never be delivered
+var errInvalidBatch = errors.New("invalid log batch")
+
+// Sink delivers batches of logs. A batch is a JSON array of DatadogLogEntry
+// values; batching, spooling and retries are handled by the DatadogHandler,
+// so a Sink only needs to deliver one batch at a time.
+type Sink interface {
+	// Name identifies the sink in messages and names its spool directory
+	Name() string
+
+	// Send delivers a batch. Errors are retried by later runs, if a spool is
+	// configured, unless they are a *SinkStatusError for a request the sink
+	// will keep rejecting or wrap errInvalidBatch.
+	Send(ctx context.Context, batch []byte) error
+}
+
+// NewSink creates the sink selected by cfg.LogSink: the Datadog logs intake
+// (the default), an OTLP/HTTP logs endpoint, or a local JSONL file. It returns
+// nil if the Datadog sink is selected and no API key is configured.
+func NewSink(cfg *config.Config) (Sink, error) {
+	switch strings.ToLower(cfg.LogSink) {
+	case "", SinkDatadog:
+		if cfg.APIKey == "" {
+			return nil, nil
+		}
+		return NewDatadogSink(cfg, "", true), nil
+
+	case SinkOTLP:
+		return NewOTLPSink(cfg.LogOTLPEndpoint, otlpHeadersFromEnv()), nil
+
+	case SinkFile:
+		path := cfg.LogSinkPath
+		if path == "" {
+			dir, err := os.UserCacheDir()
+			if err != nil {
+				return nil, fmt.Errorf("no --log-sink-path set and no cache directory: %w", err)
+			}
+			path = filepath.Join(dir, "dd", "logs.jsonl")
+		}
+		return NewFileSink(path), nil
+
+	default:
+		return nil, fmt.Errorf("unknown log sink %q (want %s, %s or %s)", cfg.LogSink, SinkDatadog, SinkOTLP, SinkFile)
+	}
+}
+
+// SinkStatusError is a non-2xx response from an HTTP sink
+type SinkStatusError struct {
+	Sink       string
+	StatusCode int
+	Status     string
+}
+
+// Error implements error.
+func (e *SinkStatusError) Error() string {
+	return fmt.Sprintf("%s returned %s", e.Sink, e.Status)
+}
+
+// isRetryable reports whether a failed send may succeed later. Network and
+// file errors, timeouts, rate limiting and server errors are retried; other
+// rejections are not.
+func isRetryable(err error) bool {
+	if errors.Is(err, errInvalidBatch) {
+		return false
+	}
+	var se *SinkStatusError
+	if !errors.As(err, &se) {
+		return true
+	}
+	return se.StatusCode == http.StatusRequestTimeout ||
+		se.StatusCode == http.StatusTooManyRequests ||
+		se.StatusCode >= 500
+}
+
+// DatadogSink sends batches to the Datadog HTTP logs intake
+type DatadogSink struct {
+	cfg      *config.Config
+	client   *http.Client
+	endpoint string
+	compress bool
+}
+
+// NewDatadogSink creates a sink for the logs intake of the configured site.
+// endpoint, when set, overrides the intake URL. Payloads are gzipped when
+// compress is true.
+func NewDatadogSink(cfg *config.Config, endpoint string, compress bool) *DatadogSink {
+	if endpoint == "" {
+		site := cfg.Site
+		if site == "" {
+			site = "datadoghq.com"
+		}
+		endpoint = fmt.Sprintf(DatadogLogsEndpoint, site)
+	}
+
+	return &DatadogSink{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: sinkTimeout},
+		endpoint: endpoint,
+		compress: compress,
+	}
+}
+
+// Name implements Sink.
+func (s *DatadogSink) Name() string {
+	return SinkDatadog
+}
+
+// Send implements Sink. The intake accepts the batch as is.
+func (s *DatadogSink) Send(ctx context.Context, batch []byte) error {
+	headers := map[string]string{"DD-API-KEY": s.cfg.APIKey}
+	return postJSON(ctx, s.client, "Datadog logs API", s.endpoint, batch, headers, s.compress)
+}
+
+// FileSink appends logs to a local file, one JSON object per line
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink creates a sink appending to the file at path. The file and its
+// directory are created when the first batch is written.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Name implements Sink.
+func (s *FileSink) Name() string {
+	return SinkFile
+}
+
+// Send implements Sink. The batch is written with a single append so that
+// concurrent runs sharing the file do not interleave lines.
+func (s *FileSink) Send(_ context.Context, batch []byte) error {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(batch, &entries); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidBatch, err)
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		if err := json.Compact(&buf, entry); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidBatch, err)
+		}
+		buf.WriteByte('\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// postJSON posts a JSON payload, gzipped if compress is true, and turns
+// non-2xx responses into a *SinkStatusError
+func postJSON(ctx context.Context, client *http.Client, name, url string, payload []byte, headers map[string]string, compress bool) error {
+	body := payload
+	if compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(payload); err != nil {
+			return fmt.Errorf("error compressing logs: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("error compressing logs: %w", err)
+		}
+		body = buf.Bytes()
+	}
+
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Add headers
+	req.Header.Set("Content-Type", "application/json")
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	// Send request
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Check response
+	if resp.StatusCode >= 300 {
+		return &SinkStatusError{Sink: name, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
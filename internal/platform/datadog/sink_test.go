@@ -0,0 +1,186 @@
+package datadog
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+func TestNewSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		want    string
+		wantErr bool
+	}{
+		{name: "datadog by default", cfg: config.Config{APIKey: "key"}, want: SinkDatadog},
+		{name: "datadog without API key", cfg: config.Config{}, want: ""},
+		{name: "otlp", cfg: config.Config{LogSink: "otlp"}, want: SinkOTLP},
+		{name: "file", cfg: config.Config{LogSink: "FILE", LogSinkPath: "logs.jsonl"}, want: SinkFile},
+		{name: "unknown", cfg: config.Config{LogSink: "syslog"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := NewSink(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := ""
+			if sink != nil {
+				got = sink.Name()
+			}
+			if got != tt.want {
+				t.Errorf("NewSink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSink_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "dd.jsonl")
+	sink := NewFileSink(path)
+
+	batches := []string{
+		`[{"message":"one"},{"message":"two"}]`,
+		"[\n  {\"message\": \"three\"}\n]",
+	}
+	for _, batch := range batches {
+		if err := sink.Send(context.Background(), []byte(batch)); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"message\":\"one\"}\n{\"message\":\"two\"}\n{\"message\":\"three\"}\n"
+	if string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+
+	if err := sink.Send(context.Background(), []byte("not json")); isRetryable(err) {
+		t.Errorf("Send() of an invalid batch error = %v, want a non-retryable error", err)
+	}
+}
+
+func TestOTLPPayload(t *testing.T) {
+	batch := `[
+		{"message":"hello","status":"warning","service":"datadog-cli","hostname":"h1","env":"prod","ddsource":"go","timestamp":1700000000000,
+		 "attributes":{"count":3,"ratio":0.5,"ok":true,"args":["a","b"]}},
+		{"message":"bye","status":"debug","service":"datadog-cli","hostname":"h1","env":"prod","ddsource":"go","timestamp":1700000000001}
+	]`
+
+	data, err := otlpPayload([]byte(batch))
+	if err != nil {
+		t.Fatalf("otlpPayload() error = %v", err)
+	}
+
+	var request otlpRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatal(err)
+	}
+	if len(request.ResourceLogs) != 1 {
+		t.Fatalf("got %d resources, want 1", len(request.ResourceLogs))
+	}
+	resource := request.ResourceLogs[0]
+	if got := *resource.Resource.Attributes[0].Value.StringValue; got != "datadog-cli" {
+		t.Errorf("service.name = %q, want datadog-cli", got)
+	}
+
+	records := resource.ScopeLogs[0].LogRecords
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	first := records[0]
+	if first.SeverityNumber != 13 || first.SeverityText != "WARN" {
+		t.Errorf("severity = %d %s, want 13 WARN", first.SeverityNumber, first.SeverityText)
+	}
+	if first.TimeUnixNano != "1700000000000000000" {
+		t.Errorf("timeUnixNano = %s, want 1700000000000000000", first.TimeUnixNano)
+	}
+	if got := *first.Body.StringValue; got != "hello" {
+		t.Errorf("body = %q, want hello", got)
+	}
+
+	attrs := make(map[string]otlpAnyValue)
+	for _, kv := range first.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["count"].IntValue; v == nil || *v != "3" {
+		t.Errorf("count = %+v, want intValue 3", attrs["count"])
+	}
+	if v := attrs["ratio"].DoubleValue; v == nil || *v != 0.5 {
+		t.Errorf("ratio = %+v, want doubleValue 0.5", attrs["ratio"])
+	}
+	if v := attrs["ok"].BoolValue; v == nil || !*v {
+		t.Errorf("ok = %+v, want boolValue true", attrs["ok"])
+	}
+	if v := attrs["args"].StringValue; v == nil || *v != `["a","b"]` {
+		t.Errorf("args = %+v, want stringValue [\"a\",\"b\"]", attrs["args"])
+	}
+	if records[1].SeverityNumber != 5 {
+		t.Errorf("debug severity = %d, want 5", records[1].SeverityNumber)
+	}
+}
+
+func TestDatadogHandler_OTLPSink(t *testing.T) {
+	var mu sync.Mutex
+	var records []otlpLogRecord
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		auth = r.Header.Get("Authorization")
+		body, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("request body is not gzipped: %v", err)
+			return
+		}
+		var request otlpRequest
+		json.NewDecoder(body).Decode(&request)
+		for _, resource := range request.ResourceLogs {
+			records = append(records, resource.ScopeLogs[0].LogRecords...)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer%20token")
+	cfg := &config.Config{LogSink: SinkOTLP, LogOTLPEndpoint: server.URL}
+	sink, err := NewSink(cfg)
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel: slog.LevelInfo,
+		Fallback: slog.NewTextHandler(io.Discard, nil),
+		Sink:     sink,
+	})
+	slog.New(h).Info("to the collector", "key", "value")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if auth != "Bearer token" {
+		t.Errorf("Authorization = %q, want the header from OTEL_EXPORTER_OTLP_HEADERS", auth)
+	}
+	if len(records) != 2 {
+		t.Fatalf("collector received %d logs, want 1 and the summary", len(records))
+	}
+	if got := *records[0].Body.StringValue; got != "to the collector" {
+		t.Errorf("body = %q, want the log message", got)
+	}
+}
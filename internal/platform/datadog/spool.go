@@ -0,0 +1,271 @@
+package datadog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSpoolMaxBytes is the default cap on the total size of the log spool
+	DefaultSpoolMaxBytes = 10 << 20
+	
+	// DefaultSpoolMaxAge is the default age after which spooled batches are dropped.
+	// The logs intake rejects entries more than 18 hours old.
+	DefaultSpoolMaxAge = 18 * time.Hour
+	
+	// spoolBaseBackoff and spoolMaxBackoff bound the delay before a batch that
+	// failed to send is retried by a later run
+	spoolBaseBackoff = 30 * time.Second
+	spoolMaxBackoff  = time.Hour
+	
+	// spoolStaleSending is how long a batch may stay claimed by a sender before
+	// it is assumed the sending process died and the batch is made pending again
+	spoolStaleSending = time.Minute
+	
+	spoolExt        = ".json"
+	spoolSendingExt = ".sending"
+	spoolTempExt    = ".tmp"
+)
+
+// spool keeps log batches on disk until they are delivered, so logs survive
+// crashes, short runs that exit before a send completes, and offline runs.
+//
+// Each batch is one file named <created-unix-nanos>-<pid>-<attempts>.json.
+// A sender claims a batch by renaming it to *.sending, which keeps concurrent
+// runs sharing the directory from sending the same batch twice.
+type spool struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+	// report is called with errors that do not fail the operation at hand
+	report func(msg string, err error)
+}
+
+// spoolFile is a batch found in the spool directory
+type spoolFile struct {
+	path     string
+	created  time.Time
+	attempts int
+	modTime  time.Time
+	size     int64
+	sending  bool
+}
+
+// newSpool creates the spool directory if needed
+func newSpool(dir string, maxBytes int64, maxAge time.Duration) (*spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log spool directory: %w", err)
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultSpoolMaxBytes
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultSpoolMaxAge
+	}
+	return &spool{dir: dir, maxBytes: maxBytes, maxAge: maxAge}, nil
+}
+
+// write stores a new batch and returns its path. The batch is written to a
+// temporary file first so a crash never leaves a partial batch behind.
+func (s *spool) write(data []byte) (string, error) {
+	name := fmt.Sprintf("%d-%d-0%s", time.Now().UnixNano(), os.Getpid(), spoolExt)
+	path := filepath.Join(s.dir, name)
+	
+	tmp := path + spoolTempExt
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write log spool: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write log spool: %w", err)
+	}
+	
+	s.prune(time.Now())
+	return path, nil
+}
+
+// claim marks a pending batch as being sent by this process and returns its
+// new path. It fails if another process claimed the batch first.
+//
+// A rename keeps the batch's old modification time, so the claim is stamped
+// with the current time; otherwise a concurrent run's prune would take the
+// batch for one whose sender died and make it pending again mid-send.
+func (s *spool) claim(path string) (string, error) {
+	sending := path + spoolSendingExt
+	if err := os.Rename(path, sending); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if err := os.Chtimes(sending, now, now); err != nil {
+		os.Rename(sending, path)
+		return "", err
+	}
+	return sending, nil
+}
+
+// release returns a claimed batch to the spool after a failed send, counting
+// the attempt so later runs back off before retrying it
+func (s *spool) release(path string) error {
+	f, ok := parseSpoolFile(path)
+	if !ok {
+		return os.Remove(path)
+	}
+	
+	name := fmt.Sprintf("%d-%d-%d%s", f.created.UnixNano(), os.Getpid(), f.attempts+1, spoolExt)
+	next := filepath.Join(s.dir, name)
+	if err := os.Rename(path, next); err != nil {
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(next, now, now)
+}
+
+// remove deletes a delivered or undeliverable batch
+func (s *spool) remove(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) && s.report != nil {
+		s.report("Error removing spooled logs", err)
+	}
+}
+
+// list returns the batches in the spool, oldest first
+func (s *spool) list() ([]spoolFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	
+	var files []spoolFile
+	for _, entry := range entries {
+		f, ok := parseSpoolFile(filepath.Join(s.dir, entry.Name()))
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		f.modTime = info.ModTime()
+		f.size = info.Size()
+		files = append(files, f)
+	}
+	
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].created.Before(files[j].created)
+	})
+	return files, nil
+}
+
+// prune drops batches older than the age cap, then the oldest batches until
+// the spool fits the size cap. Batches whose sender appears to have died are
+// made pending again.
+func (s *spool) prune(now time.Time) {
+	files, err := s.list()
+	if err != nil {
+		return
+	}
+	
+	var total int64
+	kept := files[:0]
+	for _, f := range files {
+		if now.Sub(f.created) > s.maxAge {
+			s.remove(f.path)
+			continue
+		}
+		if f.sending && now.Sub(f.modTime) > spoolStaleSending {
+			pending := strings.TrimSuffix(f.path, spoolSendingExt)
+			if os.Rename(f.path, pending) == nil {
+				f.path = pending
+			}
+		}
+		total += f.size
+		kept = append(kept, f)
+	}
+	
+	for _, f := range kept {
+		if total <= s.maxBytes {
+			break
+		}
+		s.remove(f.path)
+		total -= f.size
+	}
+	
+	// Temporary files left by a crash mid-write are never renamed into place
+	temps, _ := filepath.Glob(filepath.Join(s.dir, "*"+spoolExt+spoolTempExt))
+	for _, tmp := range temps {
+		if info, err := os.Stat(tmp); err == nil && now.Sub(info.ModTime()) > spoolStaleSending {
+			s.remove(tmp)
+		}
+	}
+}
+
+// due returns the pending batches whose retry backoff has elapsed, oldest first
+func (s *spool) due(now time.Time) []spoolFile {
+	files, err := s.list()
+	if err != nil {
+		return nil
+	}
+	
+	var due []spoolFile
+	for _, f := range files {
+		if f.sending {
+			continue
+		}
+		if f.attempts == 0 || now.Sub(f.modTime) >= spoolBackoff(f.attempts) {
+			due = append(due, f)
+		}
+	}
+	return due
+}
+
+// spoolBackoff returns the delay before a batch that failed the given number
+// of times is retried
+func spoolBackoff(attempts int) time.Duration {
+	if attempts <= 0 {
+		return 0
+	}
+	backoff := spoolBaseBackoff
+	for i := 1; i < attempts && backoff < spoolMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > spoolMaxBackoff {
+		backoff = spoolMaxBackoff
+	}
+	return backoff
+}
+
+// parseSpoolFile parses a spool file name
+func parseSpoolFile(path string) (spoolFile, bool) {
+	name := filepath.Base(path)
+	f := spoolFile{path: path}
+	
+	if strings.HasSuffix(name, spoolSendingExt) {
+		f.sending = true
+		name = strings.TrimSuffix(name, spoolSendingExt)
+	}
+	if !strings.HasSuffix(name, spoolExt) {
+		return f, false
+	}
+	
+	parts := strings.Split(strings.TrimSuffix(name, spoolExt), "-")
+	if len(parts) != 3 {
+		return f, false
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return f, false
+	}
+	attempts, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return f, false
+	}
+	
+	f.created = time.Unix(0, nanos)
+	f.attempts = attempts
+	return f, true
+}
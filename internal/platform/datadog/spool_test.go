@@ -0,0 +1,210 @@
+package datadog
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+func TestSpoolBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{20, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := spoolBackoff(tt.attempts); got != tt.want {
+			t.Errorf("spoolBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSpool_ReleaseAndDue(t *testing.T) {
+	s, err := newSpool(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := s.write([]byte(`[]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due := s.due(time.Now()); len(due) != 1 {
+		t.Fatalf("due() = %d batches, want 1 unsent batch", len(due))
+	}
+
+	// A claimed batch is not due; a failed one backs off
+	sending, err := s.claim(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due := s.due(time.Now()); len(due) != 0 {
+		t.Fatalf("due() = %d batches while sending, want 0", len(due))
+	}
+	if err := s.release(sending); err != nil {
+		t.Fatal(err)
+	}
+	if due := s.due(time.Now()); len(due) != 0 {
+		t.Fatalf("due() = %d batches right after a failure, want 0", len(due))
+	}
+
+	due := s.due(time.Now().Add(spoolBackoff(1)))
+	if len(due) != 1 || due[0].attempts != 1 {
+		t.Fatalf("due() after backoff = %+v, want one batch with 1 attempt", due)
+	}
+}
+
+func TestSpool_Prune(t *testing.T) {
+	s, err := newSpool(t.TempDir(), 5, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, _ := s.write([]byte(`[1]`))
+	newer, _ := s.write([]byte(`[2]`))
+
+	// Over the 5 byte cap, the oldest batch goes first
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("oldest batch was kept over the size cap")
+	}
+	if _, err := os.Stat(newer); err != nil {
+		t.Errorf("newest batch was dropped: %v", err)
+	}
+
+	// Past the age cap everything goes
+	s.prune(time.Now().Add(2 * time.Hour))
+	if files, _ := s.list(); len(files) != 0 {
+		t.Errorf("list() = %d batches after age cap, want 0", len(files))
+	}
+}
+
+func TestDatadogHandler_Spool(t *testing.T) {
+	var up atomic.Bool
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: "test-api-key"}
+	dir := t.TempDir()
+	newHandler := func() *DatadogHandler {
+		return NewDatadogHandler(cfg, &DatadogHandlerOptions{
+			MinLevel: slog.LevelInfo,
+			Fallback: slog.NewTextHandler(&strings.Builder{}, nil),
+			Endpoint: server.URL,
+			SpoolDir: dir,
+		})
+	}
+
+	// The intake is down: Close waits for the send and the batch stays spooled
+	h := newHandler()
+	slog.New(h).Info("kept while offline")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	files, _ := h.pipeline.spool.list()
+	if len(files) != 1 || files[0].attempts != 1 {
+		t.Fatalf("spool = %+v, want one batch with 1 attempt", files)
+	}
+
+	// Once the backoff has elapsed, the next run delivers it
+	past := time.Now().Add(-spoolBackoff(1))
+	os.Chtimes(files[0].path, past, past)
+	up.Store(true)
+
+	h = newHandler()
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if received.Load() != 1 {
+		t.Errorf("intake received %d batches, want 1", received.Load())
+	}
+	if files, _ := h.pipeline.spool.list(); len(files) != 0 {
+		t.Errorf("spool = %+v after delivery, want empty", files)
+	}
+}
+
+func TestDatadogHandler_SpoolConcurrentRuns(t *testing.T) {
+	var received atomic.Int32
+	first := make(chan struct{})
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the first send open while a second run starts and exits
+		if received.Add(1) == 1 {
+			close(first)
+			<-unblock
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIKey: "test-api-key"}
+	dir := t.TempDir()
+	newHandler := func() *DatadogHandler {
+		return NewDatadogHandler(cfg, &DatadogHandlerOptions{
+			MinLevel: slog.LevelInfo,
+			Fallback: slog.NewTextHandler(&strings.Builder{}, nil),
+			Endpoint: server.URL,
+			SpoolDir: dir,
+		})
+	}
+
+	// A batch left behind by an earlier run, older than the stale-claim limit
+	s, err := newSpool(filepath.Join(dir, SinkDatadog), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := s.write([]byte(`[{"message":"left behind"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-2 * spoolStaleSending)
+	os.Chtimes(path, past, past)
+
+	// The first run claims the batch and is still sending it...
+	a := newHandler()
+	select {
+	case <-first:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first run did not replay the spooled batch")
+	}
+
+	// ...while a second run spools, prunes and replays the same directory
+	b := newHandler()
+	slog.New(b).Info("second run")
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	close(unblock)
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if received.Load() != 2 {
+		t.Errorf("intake received %d batches, want 2 (the old batch once and the second run's)", received.Load())
+	}
+	if files, _ := s.list(); len(files) != 0 {
+		t.Errorf("spool = %+v after delivery, want empty", files)
+	}
+}
@@ -0,0 +1,316 @@
+package datadog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+// Metric names emitted by Telemetry
+const (
+	metricCommandCount    = "datadog_cli.command.count"
+	metricCommandDuration = "datadog_cli.command.duration"
+	metricAPICount        = "datadog_cli.api.request.count"
+	metricAPIDuration     = "datadog_cli.api.request.duration"
+)
+
+// Span names of commands and the API calls they make
+const (
+	commandSpanName = "cli.command"
+	apiSpanName     = "datadog.api.request"
+)
+
+// apiPathWords are the path segments of Datadog API routes. Other segments are
+// IDs or names and are replaced by "{id}" to keep endpoint tags low-cardinality.
+var apiPathWords = map[string]bool{
+	"api": true, "v1": true, "v2": true,
+	"host": true, "hosts": true, "mute": true, "unmute": true,
+	"tags": true, "monitor": true, "search": true, "groups": true,
+	"downtime": true, "cancel": true, "events": true,
+	"series": true, "distribution_points": true, "validate": true,
+}
+
+// TelemetryOptions configures Telemetry
+type TelemetryOptions struct {
+	// Metrics selects where metrics are sent: MetricsDogStatsD, MetricsAPI,
+	// or MetricsOff (or empty) for none
+	Metrics string
+
+	// DogStatsDAddr is the DogStatsD address, host:port or unix:///path
+	DogStatsDAddr string
+
+	// Traces enables sending a trace of each command and its API calls to
+	// the Datadog Agent at TraceAgentURL
+	Traces        bool
+	TraceAgentURL string
+
+	// Service and Environment tag metrics and traces
+	Service     string
+	Environment string
+
+	// CloseTimeout bounds how long Close waits to send metrics and traces
+	CloseTimeout time.Duration
+}
+
+// Telemetry emits usage metrics for CLI commands and the API calls they make
+// and, optionally, a trace linking each command to its API calls. It is the
+// metrics and traces counterpart of DatadogHandler. A nil Telemetry emits
+// nothing.
+type Telemetry struct {
+	metrics      metricsSender
+	tracer       *tracer
+	tags         []string
+	closeTimeout time.Duration
+
+	mu      sync.Mutex
+	command string
+	start   time.Time
+	span    *Span
+}
+
+// NewTelemetry creates the Telemetry selected by opts. It returns nil if
+// neither metrics nor traces are enabled.
+func NewTelemetry(cfg *config.Config, opts *TelemetryOptions) (*Telemetry, error) {
+	if opts == nil {
+		opts = &TelemetryOptions{}
+	}
+
+	service := opts.Service
+	if service == "" {
+		service = "datadog-cli"
+	}
+
+	t := &Telemetry{
+		tags:         []string{"service:" + service},
+		closeTimeout: opts.CloseTimeout,
+	}
+	if opts.Environment != "" {
+		t.tags = append(t.tags, "env:"+opts.Environment)
+	}
+	if t.closeTimeout <= 0 {
+		t.closeTimeout = DefaultCloseTimeout
+	}
+
+	switch strings.ToLower(opts.Metrics) {
+	case "", MetricsOff:
+	case MetricsDogStatsD:
+		sender, err := newDogStatsDSender(opts.DogStatsDAddr)
+		if err != nil {
+			return nil, err
+		}
+		t.metrics = sender
+	case MetricsAPI:
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "unknown"
+		}
+		t.metrics = newSeriesSender(cfg, hostname)
+	default:
+		return nil, fmt.Errorf("unknown metrics destination %q (want %s, %s or %s)", opts.Metrics, MetricsDogStatsD, MetricsAPI, MetricsOff)
+	}
+
+	if opts.Traces {
+		t.tracer = newTracer(opts.TraceAgentURL, service, opts.Environment)
+	}
+
+	if t.metrics == nil && t.tracer == nil {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// defaultTelemetry is used by the API client transport, like the default
+// slog logger is used for its logs
+var defaultTelemetry atomic.Pointer[Telemetry]
+
+// SetDefaultTelemetry makes t the Telemetry that API calls are reported to
+func SetDefaultTelemetry(t *Telemetry) {
+	defaultTelemetry.Store(t)
+}
+
+// DefaultTelemetry returns the Telemetry that API calls are reported to, or nil
+func DefaultTelemetry() *Telemetry {
+	return defaultTelemetry.Load()
+}
+
+// StartCommand starts timing a command, such as "hosts list", and starts its
+// span. API calls made until FinishCommand are children of the span.
+func (t *Telemetry) StartCommand(command string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.command = command
+	t.start = time.Now()
+	if t.tracer != nil {
+		t.span = t.tracer.start(nil, commandSpanName, command)
+	}
+}
+
+// Context returns ctx carrying the span of the current command, so that logs
+// written with it carry the command's trace and span IDs
+func (t *Telemetry) Context(ctx context.Context) context.Context {
+	if t == nil {
+		return ctx
+	}
+
+	t.mu.Lock()
+	span := t.span
+	t.mu.Unlock()
+
+	if span == nil {
+		return ctx
+	}
+	return ContextWithSpan(ctx, span)
+}
+
+// FinishCommand records the count and duration of the current command and
+// finishes its span
+func (t *Telemetry) FinishCommand(err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	command, start, span := t.command, t.start, t.span
+	t.span = nil
+	t.mu.Unlock()
+
+	if start.IsZero() {
+		return
+	}
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	tags := append(t.commandTags(command),
+		"status:"+status,
+		"exit_code:"+strconv.Itoa(ExitCode(err)))
+
+	if t.metrics != nil {
+		t.metrics.count(metricCommandCount, 1, tags)
+		t.metrics.distribution(metricCommandDuration, time.Since(start).Seconds(), tags)
+	}
+	if span != nil {
+		span.SetTag("exit_code", strconv.Itoa(ExitCode(err)))
+		span.Finish(err)
+	}
+}
+
+// startAPICall starts the span of an API call, as a child of the current
+// command's span, and returns it with a context carrying it
+func (t *Telemetry) startAPICall(ctx context.Context, req *http.Request) (*Span, context.Context) {
+	if t == nil || t.tracer == nil {
+		return nil, ctx
+	}
+
+	t.mu.Lock()
+	parent := t.span
+	t.mu.Unlock()
+
+	endpoint := apiEndpoint(req.URL.Path)
+	span := t.tracer.start(parent, apiSpanName, req.Method+" "+endpoint)
+	span.SetTag("http.method", req.Method)
+	span.SetTag("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+	return span, ContextWithSpan(ctx, span)
+}
+
+// finishAPICall records the count and latency of an API call and finishes
+// its span. resp is nil if the call failed before a response was received.
+func (t *Telemetry) finishAPICall(span *Span, req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	command := t.command
+	t.mu.Unlock()
+
+	tags := append(t.commandTags(command),
+		"method:"+req.Method,
+		"endpoint:"+apiEndpoint(req.URL.Path))
+	if resp != nil {
+		tags = append(tags,
+			"status_code:"+strconv.Itoa(resp.StatusCode),
+			fmt.Sprintf("status_class:%dxx", resp.StatusCode/100))
+	} else {
+		tags = append(tags, "status_class:error")
+	}
+
+	if t.metrics != nil {
+		t.metrics.count(metricAPICount, 1, tags)
+		t.metrics.distribution(metricAPIDuration, duration.Seconds(), tags)
+	}
+
+	if span != nil {
+		if resp != nil {
+			span.SetTag("http.status_code", strconv.Itoa(resp.StatusCode))
+			if resp.StatusCode >= 500 {
+				err = errors.New(resp.Status)
+			}
+			if id := resp.Header.Get(requestIDHeader); id != "" {
+				span.SetTag("http.request_id", id)
+			}
+		}
+		span.Finish(err)
+	}
+}
+
+// commandTags returns the common tags with the command's
+func (t *Telemetry) commandTags(command string) []string {
+	tags := append([]string{}, t.tags...)
+	if command != "" {
+		tags = append(tags, "command:"+command)
+	}
+	return tags
+}
+
+// Close sends pending metrics and traces, waiting up to the close timeout
+func (t *Telemetry) Close() error {
+	if t == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.closeTimeout)
+	defer cancel()
+
+	var errs []error
+	if t.metrics != nil {
+		if err := t.metrics.flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("error sending metrics: %w", err))
+		}
+		if err := t.metrics.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if t.tracer != nil {
+		if err := t.tracer.flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("error sending traces: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apiEndpoint normalizes an API path to its route, e.g., /api/v1/host/{id}/mute
+func apiEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if !apiPathWords[segment] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,248 @@
+package datadog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+func TestNewTelemetry(t *testing.T) {
+	tel, err := NewTelemetry(&config.Config{}, &TelemetryOptions{Metrics: MetricsOff})
+	if err != nil || tel != nil {
+		t.Errorf("NewTelemetry(off) = %v, %v, want nil, nil", tel, err)
+	}
+
+	if _, err := NewTelemetry(&config.Config{}, &TelemetryOptions{Metrics: "statsd"}); err == nil {
+		t.Error("NewTelemetry(statsd) error = nil, want an unknown destination error")
+	}
+
+	// A nil Telemetry is safe to use
+	tel.StartCommand("hosts list")
+	tel.FinishCommand(nil)
+	if err := tel.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestTelemetry_DogStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tel, err := NewTelemetry(&config.Config{}, &TelemetryOptions{
+		Metrics:       MetricsDogStatsD,
+		DogStatsDAddr: conn.LocalAddr().String(),
+		Environment:   "prod",
+	})
+	if err != nil {
+		t.Fatalf("NewTelemetry() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "https://api.datadoghq.com/api/v1/host/web-1/mute", nil)
+	resp := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}
+
+	tel.StartCommand("hosts mute")
+	tel.finishAPICall(nil, req, resp, nil, 20*time.Millisecond)
+	tel.FinishCommand(NewAPIError("muting host", resp, errors.New("403 Forbidden")))
+	if err := tel.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	var lines []string
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(lines) < 4 {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("received %d metrics, want 4: %v", len(lines), err)
+		}
+		lines = append(lines, string(buf[:n]))
+	}
+
+	want := []string{
+		"datadog_cli.api.request.count:1|c|#service:datadog-cli,env:prod,command:hosts mute,method:POST,endpoint:/api/v1/host/{id}/mute,status_code:403,status_class:4xx",
+		"datadog_cli.api.request.duration:0.02|d|#service:datadog-cli,env:prod,command:hosts mute,method:POST,endpoint:/api/v1/host/{id}/mute,status_code:403,status_class:4xx",
+		"datadog_cli.command.count:1|c|#service:datadog-cli,env:prod,command:hosts mute,status:error,exit_code:" + strconv.Itoa(ExitAuth),
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("metric %d = %q, want %q", i, lines[i], w)
+		}
+	}
+	if !strings.HasPrefix(lines[3], "datadog_cli.command.duration:") {
+		t.Errorf("metric 3 = %q, want the command duration", lines[3])
+	}
+}
+
+func TestSeriesSender_Flush(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if key := r.Header.Get("DD-API-KEY"); key != "api-key" {
+			t.Errorf("DD-API-KEY = %q, want api-key", key)
+		}
+		body, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("request body is not gzipped: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(body)
+		bodies[r.URL.Path] = string(data)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := newSeriesSender(&config.Config{APIKey: "api-key"}, "laptop")
+	sender.seriesURL = server.URL + "/api/v2/series"
+	sender.distributionURL = server.URL + "/api/v1/distribution_points"
+
+	tags := []string{"command:hosts list"}
+	sender.count(metricCommandCount, 1, tags)
+	sender.count(metricCommandCount, 1, tags)
+	sender.distribution(metricCommandDuration, 0.5, tags)
+	sender.distribution(metricCommandDuration, 1.5, tags)
+	if err := sender.flush(context.Background()); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	var series seriesPayload
+	if err := json.Unmarshal([]byte(bodies["/api/v2/series"]), &series); err != nil {
+		t.Fatalf("series body = %q: %v", bodies["/api/v2/series"], err)
+	}
+	if len(series.Series) != 1 || series.Series[0].Points[0].Value != 2 || series.Series[0].Type != seriesTypeCount {
+		t.Errorf("series = %+v, want one count of 2", series.Series)
+	}
+
+	var distributions distributionPayload
+	if err := json.Unmarshal([]byte(bodies["/api/v1/distribution_points"]), &distributions); err != nil {
+		t.Fatalf("distribution body = %q: %v", bodies["/api/v1/distribution_points"], err)
+	}
+	if len(distributions.Series) != 1 || distributions.Series[0].Host != "laptop" {
+		t.Fatalf("distributions = %+v, want one series from laptop", distributions.Series)
+	}
+	if values, _ := distributions.Series[0].Points[0][1].([]interface{}); len(values) != 2 {
+		t.Errorf("distribution points = %v, want 2 values", distributions.Series[0].Points[0])
+	}
+}
+
+func TestTelemetry_Traces(t *testing.T) {
+	var traces [][]agentSpan
+	var count string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v0.3/traces" {
+			t.Errorf("request = %s %s, want PUT /v0.3/traces", r.Method, r.URL.Path)
+		}
+		count = r.Header.Get("X-Datadog-Trace-Count")
+		json.NewDecoder(r.Body).Decode(&traces)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tel, err := NewTelemetry(&config.Config{}, &TelemetryOptions{Traces: true, TraceAgentURL: server.URL, Environment: "dev"})
+	if err != nil {
+		t.Fatalf("NewTelemetry() error = %v", err)
+	}
+
+	tel.StartCommand("monitors list")
+	req := httptest.NewRequest(http.MethodGet, "https://api.datadoghq.com/api/v1/monitor/search", nil)
+	span, ctx := tel.startAPICall(context.Background(), req)
+	if SpanFromContext(ctx) != span {
+		t.Error("startAPICall() context does not carry the API call's span")
+	}
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", Header: http.Header{requestIDHeader: {"req-1"}}}
+	tel.finishAPICall(span, req, resp, nil, time.Millisecond)
+	tel.FinishCommand(errors.New("failed"))
+	if err := tel.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if count != "1" || len(traces) != 1 || len(traces[0]) != 2 {
+		t.Fatalf("agent received %v traces (count %q), want 1 trace of 2 spans", traces, count)
+	}
+	api, command := traces[0][0], traces[0][1]
+	if command.Name != commandSpanName || command.Resource != "monitors list" || command.ParentID != 0 || command.Error != 1 {
+		t.Errorf("command span = %+v", command)
+	}
+	if api.Name != apiSpanName || api.Type != "http" || api.Resource != "GET /api/v1/monitor/search" {
+		t.Errorf("API span = %+v", api)
+	}
+	if api.TraceID != command.TraceID || api.ParentID != command.SpanID {
+		t.Errorf("API span is not a child of the command span: %+v, %+v", api, command)
+	}
+	if api.Error != 1 || api.Meta["http.status_code"] != "502" || api.Meta["http.request_id"] != "req-1" {
+		t.Errorf("API span = %+v, want a failed 502 with its request ID", api)
+	}
+}
+
+func TestDatadogHandler_TraceCorrelation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.jsonl")
+	cfg := &config.Config{}
+	h := NewDatadogHandler(cfg, &DatadogHandlerOptions{
+		MinLevel: slog.LevelInfo,
+		Fallback: slog.NewTextHandler(io.Discard, nil),
+		Sink:     NewFileSink(path),
+	})
+
+	tracer := newTracer("http://localhost:0", "datadog-cli", "")
+	span := tracer.start(nil, commandSpanName, "hosts list")
+	slog.New(h).InfoContext(ContextWithSpan(context.Background(), span), "in a span")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan()
+	var entry struct {
+		Message    string                 `json:"message"`
+		Attributes map[string]interface{} `json:"attributes"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+		t.Fatalf("log = %q: %v", scanner.Text(), err)
+	}
+	if entry.Attributes["dd.trace_id"] != strconv.FormatUint(span.TraceID, 10) ||
+		entry.Attributes["dd.span_id"] != strconv.FormatUint(span.SpanID, 10) {
+		t.Errorf("log attributes = %v, want the span's trace and span IDs", entry.Attributes)
+	}
+}
+
+func TestAPIEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/hosts":                    "/api/v1/hosts",
+		"/api/v1/host/web-1/unmute":        "/api/v1/host/{id}/unmute",
+		"/api/v1/tags/hosts/web-1":         "/api/v1/tags/hosts/{id}",
+		"/api/v1/monitor/12345":            "/api/v1/monitor/{id}",
+		"/api/v2/downtime/abc-123/":        "/api/v2/downtime/{id}",
+		"/api/v1/downtime/cancel/by_scope": "/api/v1/downtime/cancel/{id}",
+	}
+	for path, want := range tests {
+		if got := apiEndpoint(path); got != want {
+			t.Errorf("apiEndpoint(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTraceAgentURL is the trace intake of a local Datadog Agent
+const DefaultTraceAgentURL = "http://localhost:8126"
+
+// Span is a timed operation of a trace: a command, or an API call made by it
+type Span struct {
+	TraceID  uint64
+	SpanID   uint64
+	ParentID uint64
+	Name     string
+	Resource string
+	Start    time.Time
+	Duration time.Duration
+	Error    bool
+	Meta     map[string]string
+
+	tracer *tracer
+}
+
+// spanKey is the context key of the current span
+type spanKey struct{}
+
+// ContextWithSpan returns a context carrying span as the current span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the current span of ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SetTag sets a tag on the span
+func (s *Span) SetTag(key, value string) {
+	if s == nil {
+		return
+	}
+	s.Meta[key] = value
+}
+
+// Finish ends the span, marking it as failed if err is not nil, and queues it
+// to be sent with its trace
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.Duration = time.Since(s.Start)
+	if err != nil {
+		s.Error = true
+		s.Meta["error.message"] = err.Error()
+	}
+	s.tracer.finish(s)
+}
+
+// tracer collects finished spans and sends them to the Datadog Agent's trace
+// intake when it is flushed. The CLI makes one trace per command, so spans are
+// kept in memory until exit.
+type tracer struct {
+	client  *http.Client
+	url     string
+	service string
+	env     string
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// newTracer creates a tracer for the agent at agentURL. An empty agentURL uses
+// DD_TRACE_AGENT_URL, then DD_AGENT_HOST, then DefaultTraceAgentURL.
+func newTracer(agentURL, service, env string) *tracer {
+	if agentURL == "" {
+		agentURL = os.Getenv("DD_TRACE_AGENT_URL")
+	}
+	if agentURL == "" {
+		if host := os.Getenv("DD_AGENT_HOST"); host != "" {
+			agentURL = "http://" + host + ":8126"
+		}
+	}
+	if agentURL == "" {
+		agentURL = DefaultTraceAgentURL
+	}
+
+	return &tracer{
+		client:  &http.Client{Timeout: sinkTimeout},
+		url:     strings.TrimSuffix(agentURL, "/") + "/v0.3/traces",
+		service: service,
+		env:     env,
+	}
+}
+
+// start starts a span, as a child of parent if it is not nil
+func (t *tracer) start(parent *Span, name, resource string) *Span {
+	span := &Span{
+		SpanID:   rand.Uint64(),
+		Name:     name,
+		Resource: resource,
+		Start:    time.Now(),
+		Meta:     make(map[string]string),
+		tracer:   t,
+	}
+	if parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = rand.Uint64()
+	}
+	if t.env != "" {
+		span.Meta["env"] = t.env
+	}
+	return span
+}
+
+// finish queues a finished span
+func (t *tracer) finish(span *Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, span)
+}
+
+// agentSpan is a span as encoded for the agent's v0.3 trace intake
+type agentSpan struct {
+	TraceID  uint64            `json:"trace_id"`
+	SpanID   uint64            `json:"span_id"`
+	ParentID uint64            `json:"parent_id,omitempty"`
+	Name     string            `json:"name"`
+	Resource string            `json:"resource"`
+	Service  string            `json:"service"`
+	Type     string            `json:"type"`
+	Start    int64             `json:"start"`
+	Duration int64             `json:"duration"`
+	Error    int32             `json:"error"`
+	Meta     map[string]string `json:"meta,omitempty"`
+}
+
+// flush sends the finished spans, grouped by trace
+func (t *tracer) flush(ctx context.Context) error {
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	traces := make(map[uint64][]agentSpan)
+	order := make([]uint64, 0)
+	for _, span := range spans {
+		if _, ok := traces[span.TraceID]; !ok {
+			order = append(order, span.TraceID)
+		}
+		encoded := agentSpan{
+			TraceID:  span.TraceID,
+			SpanID:   span.SpanID,
+			ParentID: span.ParentID,
+			Name:     span.Name,
+			Resource: span.Resource,
+			Service:  t.service,
+			Type:     "custom",
+			Start:    span.Start.UnixNano(),
+			Duration: span.Duration.Nanoseconds(),
+			Meta:     span.Meta,
+		}
+		if span.Name == apiSpanName {
+			encoded.Type = "http"
+		}
+		if span.Error {
+			encoded.Error = 1
+		}
+		traces[span.TraceID] = append(traces[span.TraceID], encoded)
+	}
+
+	payload := make([][]agentSpan, 0, len(order))
+	for _, id := range order {
+		payload = append(payload, traces[id])
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, t.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Datadog-Trace-Count", fmt.Sprint(len(payload)))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return &SinkStatusError{Sink: "Datadog trace agent", StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package schemas lists the output types with published JSON Schemas. The
+// schemas under schemas/ at the repository root are generated from these
+// types; run "go test ./internal/schemas -update" after changing them.
+package schemas
+
+import (
+	"github.com/padawandba/datadog-cli/internal/audit"
+	"github.com/padawandba/datadog-cli/internal/downtimes"
+	"github.com/padawandba/datadog-cli/internal/hosts"
+	"github.com/padawandba/datadog-cli/internal/monitors"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+	"github.com/padawandba/datadog-cli/internal/tags"
+)
+
+// Dir is the directory of the published schemas, relative to the repository root
+const Dir = "schemas/v1"
+
+// Types lists every output type with a published schema
+var Types = []interface{}{
+	hosts.SimplifiedHost{},
+	monitors.Monitor{},
+	monitors.StatusSummary{},
+	monitors.FacetCount{},
+	monitors.BulkResult{},
+	monitors.SilencedScope{},
+	downtimes.SimplifiedDowntime{},
+	tags.SimplifiedHostTags{},
+	tags.SimplifiedTagsBySource{},
+	audit.Entry{},
+}
+
+// Generate returns the envelope schema of every published type, keyed by file name
+func Generate() (map[string][]byte, error) {
+	files := make(map[string][]byte, len(Types))
+	for _, t := range Types {
+		schema, err := console.EnvelopeSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		files[console.SchemaFileName(t)] = schema
+	}
+	return files, nil
+}
@@ -0,0 +1,128 @@
+package schemas
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/hosts"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+)
+
+var update = flag.Bool("update", false, "rewrite the published schemas from the Go types")
+
+// schemaDir is the published schema directory relative to this package
+var schemaDir = filepath.Join("..", "..", Dir)
+
+// TestPublishedSchemas fails when a change to an output type is not
+// reflected in the published schemas
+func TestPublishedSchemas(t *testing.T) {
+	files, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if *update {
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(schemaDir, name), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(schemaDir, name))
+		if err != nil {
+			t.Errorf("published schema %s is missing: %v (run go test ./internal/schemas -update)", name, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("published schema %s is out of date (run go test ./internal/schemas -update)", name)
+		}
+	}
+
+	// Every published schema must still be generated
+	published, _ := filepath.Glob(filepath.Join(schemaDir, "*.schema.json"))
+	for _, path := range published {
+		if _, ok := files[filepath.Base(path)]; !ok {
+			t.Errorf("published schema %s no longer matches an output type", filepath.Base(path))
+		}
+	}
+}
+
+// TestEnvelopeMatchesSchema checks envelope output against the properties and
+// required fields of its published schema
+func TestEnvelopeMatchesSchema(t *testing.T) {
+	formatter, err := console.NewFormatterFromConfig(&config.Config{
+		Output:       "json",
+		Site:         "datadoghq.eu",
+		Profile:      "default",
+		JSONEnvelope: true,
+	})
+	if err != nil {
+		t.Fatalf("NewFormatterFromConfig() error = %v", err)
+	}
+	var buf bytes.Buffer
+	formatter.WithWriter(&buf)
+
+	host := hosts.SimplifiedHost{Name: "web-01", Up: true, LastReportedAt: time.Now().Format(time.RFC3339)}
+	if err := formatter.Format([]hosts.SimplifiedHost{host}); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+		t.Fatalf("envelope is not valid JSON: %v", err)
+	}
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Const string `json:"const"`
+		} `json:"properties"`
+		Defs map[string]struct {
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	data, err := os.ReadFile(filepath.Join(schemaDir, console.SchemaFileName(host)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := envelope[name]; !ok {
+			t.Errorf("envelope is missing required field %q", name)
+		}
+	}
+	if envelope["kind"] != schema.Properties["kind"].Const {
+		t.Errorf("kind = %v, want %q", envelope["kind"], schema.Properties["kind"].Const)
+	}
+	if envelope["apiVersion"] != schema.Properties["apiVersion"].Const {
+		t.Errorf("apiVersion = %v, want %q", envelope["apiVersion"], schema.Properties["apiVersion"].Const)
+	}
+
+	metadata := envelope["metadata"].(map[string]interface{})
+	for _, name := range schema.Defs["EnvelopeMetadata"].Required {
+		if _, ok := metadata[name]; !ok {
+			t.Errorf("metadata is missing required field %q", name)
+		}
+	}
+	if metadata["site"] != "datadoghq.eu" || metadata["total"] != float64(1) {
+		t.Errorf("metadata = %v", metadata)
+	}
+
+	item := envelope["items"].([]interface{})[0].(map[string]interface{})
+	for _, name := range schema.Defs["Host"].Required {
+		if _, ok := item[name]; !ok {
+			t.Errorf("item is missing required field %q", name)
+		}
+	}
+}
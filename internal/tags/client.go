@@ -6,6 +6,8 @@ import (
 
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	"github.com/padawandba/datadog-cli/internal/audit"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
 )
 
 // Client provides tag-related operations
@@ -35,18 +37,68 @@ func (c *Client) GetHostTags(hostname string, source string) ([]string, error) {
 	// Use proper error handling with context
 	resp, httpResp, err := tagsAPI.GetHostTags(c.ctx, hostname, *opts)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return nil, fmt.Errorf("error getting host tags (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return nil, fmt.Errorf("error getting host tags: %v", err)
+		return nil, ddapi.NewAPIError("getting host tags", httpResp, err)
 	}
 	
 	return resp.GetTags(), nil
 }
 
+// hostTagsState is the audited state of a host's tags from one source
+type hostTagsState struct {
+	Source string   `json:"source,omitempty"`
+	Tags   []string `json:"tags"`
+}
+
 // AddHostTags adds tags to a specific host
 func (c *Client) AddHostTags(hostname string, tags []string, source string) error {
+	// The API does not return the tags a host had before, so they are read first
+	var before interface{}
+	if state := c.tagsState(hostname, source); state != nil {
+		before = state
+	}
+	
+	var after interface{}
+	state, err := c.createHostTags(hostname, tags, source)
+	if err == nil {
+		after = state
+	}
+	
+	audit.FromContext(c.ctx).Record(c.ctx, "tags.add", "host:"+hostname, before, after, err)
+	return err
+}
+
+// RemoveHostTags removes tags from a specific host
+func (c *Client) RemoveHostTags(hostname string, tags []string, source string) error {
+	var before, after interface{}
+	current, state, err := c.removeHostTags(hostname, tags, source)
+	if current != nil {
+		before = current
+	}
+	if err == nil {
+		after = state
+	}
+	
+	audit.FromContext(c.ctx).Record(c.ctx, "tags.remove", "host:"+hostname, before, after, err)
+	return err
+}
+
+// tagsState returns a host's tags from source for the audit log, or nil if
+// they cannot be retrieved. It costs an API call, so it is only made while
+// changes are audited.
+func (c *Client) tagsState(hostname string, source string) *hostTagsState {
+	if audit.FromContext(c.ctx) == nil {
+		return nil
+	}
+	
+	tags, err := c.GetHostTags(hostname, source)
+	if err != nil {
+		return nil
+	}
+	return &hostTagsState{Source: source, Tags: tags}
+}
+
+// createHostTags adds tags to a host and returns the resulting tags
+func (c *Client) createHostTags(hostname string, tags []string, source string) (*hostTagsState, error) {
 	tagsAPI := datadogV1.NewTagsApi(c.apiClient)
 	
 	// Create the request body with proper initialization
@@ -60,20 +112,17 @@ func (c *Client) AddHostTags(hostname string, tags []string, source string) erro
 	}
 	
 	// Use proper error handling with context
-	_, httpResp, err := tagsAPI.CreateHostTags(c.ctx, hostname, body, *opts)
+	resp, httpResp, err := tagsAPI.CreateHostTags(c.ctx, hostname, body, *opts)
 	if err != nil {
-		// Include HTTP response details in error if available
-		if httpResp != nil {
-			return fmt.Errorf("error adding host tags (status: %d): %v", httpResp.StatusCode, err)
-		}
-		return fmt.Errorf("error adding host tags: %v", err)
+		return nil, ddapi.NewAPIError("adding host tags", httpResp, err)
 	}
 	
-	return nil
+	return &hostTagsState{Source: source, Tags: resp.GetTags()}, nil
 }
 
-// RemoveHostTags removes tags from a specific host
-func (c *Client) RemoveHostTags(hostname string, tags []string, source string) error {
+// removeHostTags removes tags from a host and returns the tags before the
+// change, if they were read, and the remaining tags
+func (c *Client) removeHostTags(hostname string, tags []string, source string) (*hostTagsState, *hostTagsState, error) {
 	tagsAPI := datadogV1.NewTagsApi(c.apiClient)
 	
 	// Create optional parameters with proper initialization
@@ -82,24 +131,24 @@ func (c *Client) RemoveHostTags(hostname string, tags []string, source string) e
 		opts = opts.WithSource(source)
 	}
 	
+	removed := &hostTagsState{Source: source, Tags: []string{}}
+	
 	if len(tags) == 0 || (len(tags) == 1 && tags[0] == "*") {
-		// Delete all tags
+		// Delete all tags; the API does not return what was deleted
+		before := c.tagsState(hostname, source)
 		httpResp, err := tagsAPI.DeleteHostTags(c.ctx, hostname, *opts)
 		if err != nil {
-			// Include HTTP response details in error if available
-			if httpResp != nil {
-				return fmt.Errorf("error removing all host tags (status: %d): %v", httpResp.StatusCode, err)
-			}
-			return fmt.Errorf("error removing all host tags: %v", err)
+			return before, nil, ddapi.NewAPIError("removing all host tags", httpResp, err)
 		}
-		return nil
+		return before, removed, nil
 	}
 	
 	// For specific tags, we need to get current tags, filter them, and update
 	currentTags, err := c.GetHostTags(hostname, source)
 	if err != nil {
-		return fmt.Errorf("error getting current host tags: %v", err)
+		return nil, nil, fmt.Errorf("error getting current host tags: %w", err)
 	}
+	before := &hostTagsState{Source: source, Tags: currentTags}
 	
 	// Filter out the tags to be removed
 	newTags := filterTags(currentTags, tags)
@@ -108,17 +157,14 @@ func (c *Client) RemoveHostTags(hostname string, tags []string, source string) e
 	if len(newTags) == 0 {
 		httpResp, err := tagsAPI.DeleteHostTags(c.ctx, hostname, *opts)
 		if err != nil {
-			// Include HTTP response details in error if available
-			if httpResp != nil {
-				return fmt.Errorf("error removing all host tags (status: %d): %v", httpResp.StatusCode, err)
-			}
-			return fmt.Errorf("error removing all host tags: %v", err)
+			return before, nil, ddapi.NewAPIError("removing all host tags", httpResp, err)
 		}
-		return nil
+		return before, removed, nil
 	}
 	
 	// Update with the filtered tags
-	return c.AddHostTags(hostname, newTags, source)
+	after, err := c.createHostTags(hostname, newTags, source)
+	return before, after, err
 }
 
 // filterTags removes the specified tags from the list of current tags
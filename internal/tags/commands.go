@@ -46,18 +46,25 @@ func listCommand(client *Client, cfg *config.Config) *cli.Command {
 			hostname := c.Args().First()
 			source := c.String("source")
 			
-			tags, err := client.GetHostTags(hostname, source)
+			formatter, err := console.NewFormatterFromConfig(cfg)
 			if err != nil {
-				return fmt.Errorf("failed to get host tags: %v", err)
+				return err
 			}
 			
-			formatter := console.NewFormatter(cfg.Output)
-			if c.String("output") != "" {
-				formatter = console.NewFormatter(c.String("output"))
+			render := func() error {
+				tags, err := client.GetHostTags(hostname, source)
+				if err != nil {
+					return fmt.Errorf("failed to get host tags: %w", err)
+				}
+				
+				// Use our custom formatter for host tags
+				return FormatHostTags(formatter, hostname, tags)
 			}
 			
-			// Use our custom formatter for host tags
-			return FormatHostTags(formatter, hostname, tags)
+			if cfg.Watch > 0 {
+				return console.Watch(c.Context, formatter, cfg.Watch, "tags list "+hostname, render)
+			}
+			return render()
 		},
 	}
 }
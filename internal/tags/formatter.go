@@ -10,6 +10,11 @@ type SimplifiedHostTags struct {
 	Tags []string `json:"tags"`
 }
 
+// Kind names host tags in JSON envelopes and schemas
+func (SimplifiedHostTags) Kind() string {
+	return "HostTags"
+}
+
 // FormatHostTags formats host tags for display
 func FormatHostTags(formatter *console.Formatter, host string, tags []string) error {
 	// Create a simplified representation
@@ -28,6 +33,11 @@ type SimplifiedTagsBySource struct {
 	Tags   []string `json:"tags"`
 }
 
+// Kind names tags grouped by source in JSON envelopes and schemas
+func (SimplifiedTagsBySource) Kind() string {
+	return "TagSource"
+}
+
 // FormatTagsBySource formats tags by source for display
 func FormatTagsBySource(formatter *console.Formatter, tagsBySource map[string][]string) error {
 	// Convert the map to a slice of SimplifiedTagsBySource for better display
@@ -0,0 +1,635 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/hosts"
+	"github.com/padawandba/datadog-cli/internal/monitors"
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
+	"github.com/padawandba/datadog-cli/internal/tags"
+)
+
+// Tab indices
+const (
+	hostsTab = iota
+	monitorsTab
+)
+
+// tagSource is the tag source used when editing host tags, matching the
+// default of the tags commands
+const tagSource = "user"
+
+// field is a labelled value shown in the detail pane
+type field struct {
+	name  string
+	value string
+}
+
+// entry is a row of a tab's list
+type entry struct {
+	// id identifies the row across refreshes: a host name or monitor ID
+	id string
+	// name is shown in prompts and status messages
+	name string
+	// cells are the list columns, matching the tab's headers
+	cells []string
+	// search is the text matched by the fuzzy filter
+	search string
+	// detail is shown in the detail pane when the row is selected
+	detail []field
+}
+
+// tab is a list of resources with its own filter and selection
+type tab struct {
+	title   string
+	headers []string
+	load    func() ([]entry, error)
+	
+	entries []entry
+	// visible holds the indices of entries matching filter, best match first
+	visible []int
+	filter  string
+	cursor  int
+	offset  int
+	
+	loading   bool
+	loadedAt  time.Time
+	loadError error
+}
+
+// applyFilter recomputes the visible rows, keeping the selected row if it still matches
+func (t *tab) applyFilter() {
+	selected := t.selectedID()
+	
+	type match struct {
+		index int
+		score int
+	}
+	matches := make([]match, 0, len(t.entries))
+	for i, e := range t.entries {
+		if score, ok := fuzzyScore(t.filter, e.search); ok {
+			matches = append(matches, match{index: i, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	
+	t.visible = make([]int, len(matches))
+	for i, m := range matches {
+		t.visible[i] = m.index
+	}
+	
+	t.cursor = 0
+	for i, index := range t.visible {
+		if t.entries[index].id == selected {
+			t.cursor = i
+			break
+		}
+	}
+}
+
+// selected returns the selected entry, or nil if the list is empty
+func (t *tab) selected() *entry {
+	if t.cursor < 0 || t.cursor >= len(t.visible) {
+		return nil
+	}
+	return &t.entries[t.visible[t.cursor]]
+}
+
+// selectedID returns the ID of the selected entry, or ""
+func (t *tab) selectedID() string {
+	if e := t.selected(); e != nil {
+		return e.id
+	}
+	return ""
+}
+
+// move moves the cursor by delta rows, staying within the list
+func (t *tab) move(delta int) {
+	t.cursor = max(0, min(t.cursor+delta, len(t.visible)-1))
+}
+
+// loadedEvent carries the result of reloading a tab
+type loadedEvent struct {
+	tab     int
+	entries []entry
+	err     error
+}
+
+// actionEvent carries the result of a mute, unmute or tag action
+type actionEvent struct {
+	tab     int
+	message string
+	err     error
+}
+
+// mode is what key presses currently edit
+type mode int
+
+const (
+	normalMode mode = iota
+	filterMode
+	promptMode
+)
+
+// app is the state of the interactive browser
+type app struct {
+	ctx      context.Context
+	hosts    *hosts.Client
+	monitors *monitors.Client
+	tags     *tags.Client
+	site     string
+	refresh  time.Duration
+	out      io.Writer
+	redactor *ddapi.Redactor
+	
+	tabs   []*tab
+	active int
+	
+	mode     mode
+	prompt   string
+	input    string
+	onSubmit func(string)
+	
+	status      string
+	statusError bool
+	
+	events chan interface{}
+	width  int
+	height int
+}
+
+// newApp creates the browser with a hosts tab and a monitors tab
+func newApp(ctx context.Context, hostsClient *hosts.Client, monitorsClient *monitors.Client, tagsClient *tags.Client, site string, refresh time.Duration, redactor *ddapi.Redactor) *app {
+	a := &app{
+		ctx:      ctx,
+		hosts:    hostsClient,
+		monitors: monitorsClient,
+		tags:     tagsClient,
+		site:     site,
+		refresh:  refresh,
+		out:      os.Stdout,
+		redactor: redactor,
+		events:   make(chan interface{}, 16),
+	}
+	
+	a.tabs = []*tab{
+		{title: "Hosts", headers: []string{"NAME", "UP", "MUTED"}, load: a.loadHosts},
+		{title: "Monitors", headers: []string{"STATUS", "NAME", "TYPE"}, load: a.loadMonitors},
+	}
+	return a
+}
+
+// run shows the browser until the user quits or ctx is canceled
+func (a *app) run() error {
+	if !console.IsTerminal(os.Stdin) || !console.IsTerminal(a.out) {
+		return fmt.Errorf("dd ui needs an interactive terminal")
+	}
+	
+	restore, err := console.MakeRaw(os.Stdin)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	
+	// Logs written to the same terminal would corrupt the screen, so local
+	// logs to the terminal are held and warnings are shown on the status line
+	// instead while the UI is open. Logs still reach the log file and sink.
+	release := console.Stderr.Hold()
+	defer release()
+	previous := slog.Default()
+	status := statusLogHandler{next: previous.Handler(), events: a.events}
+	slog.SetDefault(slog.New(ddapi.NewRedactingHandler(status, a.redactor)))
+	defer slog.SetDefault(previous)
+	
+	// Use the alternate screen so the shell is left untouched, and hide the cursor
+	fmt.Fprint(a.out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(a.out, "\x1b[?25h\x1b[?1049l")
+	
+	go a.readKeys(os.Stdin)
+	for i := range a.tabs {
+		a.load(i)
+	}
+	
+	refresh := time.NewTicker(a.refresh)
+	defer refresh.Stop()
+	
+	// The terminal size is polled so the screen follows window resizes
+	resize := time.NewTicker(500 * time.Millisecond)
+	defer resize.Stop()
+	
+	a.draw()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case <-refresh.C:
+			for i := range a.tabs {
+				a.load(i)
+			}
+		case <-resize.C:
+			if width, height := console.TerminalSize(a.out); width == a.width && height == a.height {
+				continue
+			}
+		case ev := <-a.events:
+			if quit := a.handle(ev); quit {
+				return nil
+			}
+		}
+		a.draw()
+	}
+}
+
+// load reloads a tab in the background
+func (a *app) load(index int) {
+	t := a.tabs[index]
+	if t.loading {
+		return
+	}
+	t.loading = true
+	
+	go func() {
+		entries, err := t.load()
+		a.events <- loadedEvent{tab: index, entries: entries, err: err}
+	}()
+}
+
+// handle applies an event and reports whether the browser should exit
+func (a *app) handle(ev interface{}) bool {
+	switch ev := ev.(type) {
+	case loadedEvent:
+		t := a.tabs[ev.tab]
+		t.loading = false
+		t.loadError = ev.err
+		if ev.err != nil {
+			a.setStatus("", ev.err)
+			break
+		}
+		t.entries = ev.entries
+		t.loadedAt = time.Now()
+		t.applyFilter()
+		
+	case logEvent:
+		a.setStatus(ev.message, nil)
+		a.statusError = true
+		
+	case actionEvent:
+		a.setStatus(ev.message, ev.err)
+		if ev.err == nil {
+			a.load(ev.tab)
+		}
+		
+	case key:
+		switch a.mode {
+		case filterMode:
+			a.handleFilterKey(ev)
+		case promptMode:
+			a.handlePromptKey(ev)
+		default:
+			return a.handleKey(ev)
+		}
+	}
+	return false
+}
+
+// handleKey handles a key press in normal mode
+func (a *app) handleKey(k key) bool {
+	t := a.tabs[a.active]
+	page := max(a.listHeight()-1, 1)
+	
+	switch k.name {
+	case "ctrl-c":
+		return true
+	case "tab", "right":
+		a.active = (a.active + 1) % len(a.tabs)
+	case "shift-tab", "left":
+		a.active = (a.active + len(a.tabs) - 1) % len(a.tabs)
+	case "up":
+		t.move(-1)
+	case "down":
+		t.move(1)
+	case "pgup":
+		t.move(-page)
+	case "pgdown":
+		t.move(page)
+	case "home":
+		t.move(-len(t.visible))
+	case "end":
+		t.move(len(t.visible))
+	case "esc":
+		t.filter = ""
+		t.applyFilter()
+	}
+	
+	switch k.r {
+	case 'q':
+		return true
+	case '1', '2':
+		a.active = int(k.r - '1')
+	case 'k':
+		t.move(-1)
+	case 'j':
+		t.move(1)
+	case 'g':
+		t.move(-len(t.visible))
+	case 'G':
+		t.move(len(t.visible))
+	case '/':
+		a.mode = filterMode
+	case 'r':
+		for i := range a.tabs {
+			a.load(i)
+		}
+		a.setStatus("Refreshing...", nil)
+	case 'm':
+		a.mute()
+	case 'u':
+		a.unmute()
+	case 'a':
+		a.editTags(true)
+	case 'd':
+		a.editTags(false)
+	}
+	return false
+}
+
+// handleFilterKey edits the active tab's filter, applying it as it is typed
+func (a *app) handleFilterKey(k key) {
+	t := a.tabs[a.active]
+	switch k.name {
+	case "enter":
+		a.mode = normalMode
+		return
+	case "esc":
+		t.filter = ""
+		a.mode = normalMode
+	case "backspace":
+		t.filter = dropLastRune(t.filter)
+	case "ctrl-u":
+		t.filter = ""
+	case "ctrl-c":
+		a.mode = normalMode
+	case "up":
+		t.move(-1)
+		return
+	case "down":
+		t.move(1)
+		return
+	case "":
+		t.filter += string(k.r)
+	default:
+		return
+	}
+	t.applyFilter()
+}
+
+// handlePromptKey edits the answer to a prompt
+func (a *app) handlePromptKey(k key) {
+	switch k.name {
+	case "enter":
+		a.mode = normalMode
+		a.onSubmit(a.input)
+	case "esc", "ctrl-c":
+		a.mode = normalMode
+		a.setStatus("Cancelled", nil)
+	case "backspace":
+		a.input = dropLastRune(a.input)
+	case "ctrl-u":
+		a.input = ""
+	case "":
+		a.input += string(k.r)
+	}
+}
+
+// ask prompts for a line of input and calls onSubmit with the answer
+func (a *app) ask(prompt string, onSubmit func(string)) {
+	a.mode = promptMode
+	a.prompt = prompt
+	a.input = ""
+	a.onSubmit = onSubmit
+}
+
+// setStatus shows a message, or err if it is not nil, on the status line
+func (a *app) setStatus(message string, err error) {
+	a.status = message
+	a.statusError = err != nil
+	if err != nil {
+		a.status = err.Error()
+	}
+}
+
+// perform runs an action in the background and reports its result
+func (a *app) perform(tabIndex int, message string, action func() error) {
+	a.setStatus(message+"...", nil)
+	go func() {
+		err := action()
+		a.events <- actionEvent{tab: tabIndex, message: message + ": done", err: err}
+	}()
+}
+
+// mute prompts for a duration and mutes the selected host or monitor
+func (a *app) mute() {
+	tabIndex := a.active
+	e := a.tabs[tabIndex].selected()
+	if e == nil {
+		return
+	}
+	
+	name := e.name
+	a.ask(fmt.Sprintf("Mute %s for (e.g., 1h; empty for indefinitely): ", name), func(answer string) {
+		var duration time.Duration
+		if answer = strings.TrimSpace(answer); answer != "" {
+			var err error
+			if duration, err = time.ParseDuration(answer); err != nil {
+				a.setStatus("", fmt.Errorf("invalid duration %q", answer))
+				return
+			}
+		}
+		
+		var end time.Time
+		if duration > 0 {
+			end = time.Now().Add(duration)
+		}
+		
+		id := e.id
+		if tabIndex == hostsTab {
+			a.perform(tabIndex, "Muting host "+name, func() error {
+				return a.hosts.Mute(id, "", end)
+			})
+			return
+		}
+		
+		monitorID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			a.setStatus("", err)
+			return
+		}
+		var endTime int64
+		if !end.IsZero() {
+			endTime = end.Unix()
+		}
+		a.perform(tabIndex, "Muting monitor "+name, func() error {
+			_, err := a.monitors.Mute(monitorID, "", endTime)
+			return err
+		})
+	})
+}
+
+// unmute unmutes the selected host or monitor
+func (a *app) unmute() {
+	tabIndex := a.active
+	e := a.tabs[tabIndex].selected()
+	if e == nil {
+		return
+	}
+	
+	id, name := e.id, e.name
+	if tabIndex == hostsTab {
+		a.perform(tabIndex, "Unmuting host "+name, func() error {
+			return a.hosts.Unmute(id)
+		})
+		return
+	}
+	
+	monitorID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		a.setStatus("", err)
+		return
+	}
+	a.perform(tabIndex, "Unmuting monitor "+name, func() error {
+		return a.monitors.Unmute(monitorID, "")
+	})
+}
+
+// editTags prompts for tags to add to or remove from the selected host
+func (a *app) editTags(add bool) {
+	if a.active != hostsTab {
+		a.setStatus("Tags can only be edited on the Hosts tab", nil)
+		return
+	}
+	e := a.tabs[hostsTab].selected()
+	if e == nil {
+		return
+	}
+	
+	host := e.id
+	prompt := fmt.Sprintf("Add tags to %s (comma-separated): ", host)
+	if !add {
+		prompt = fmt.Sprintf("Remove tags from %s (comma-separated): ", host)
+	}
+	
+	a.ask(prompt, func(answer string) {
+		var tagList []string
+		for _, tag := range strings.Split(answer, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tagList = append(tagList, tag)
+			}
+		}
+		// An empty list would remove every tag, so it is never sent
+		if len(tagList) == 0 {
+			a.setStatus("No tags entered", nil)
+			return
+		}
+		
+		if add {
+			a.perform(hostsTab, "Adding tags to "+host, func() error {
+				return a.tags.AddHostTags(host, tagList, tagSource)
+			})
+			return
+		}
+		a.perform(hostsTab, "Removing tags from "+host, func() error {
+			return a.tags.RemoveHostTags(host, tagList, tagSource)
+		})
+	})
+}
+
+// loadHosts fetches the hosts tab
+func (a *app) loadHosts() ([]entry, error) {
+	list, err := a.hosts.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	
+	entries := make([]entry, 0, len(list))
+	for _, host := range hosts.SimplifyHosts(list) {
+		muted := ""
+		if host.IsMuted {
+			muted = "muted"
+		}
+		entries = append(entries, entry{
+			id:     host.Name,
+			name:   host.Name,
+			cells:  []string{host.Name, strconv.FormatBool(host.Up), muted},
+			search: host.Name + " " + strings.Join(host.Aliases, " ") + " " + host.TagsBySource,
+			detail: []field{
+				{"Name", host.Name},
+				{"Host name", host.HostName},
+				{"Up", strconv.FormatBool(host.Up)},
+				{"Muted", strconv.FormatBool(host.IsMuted)},
+				{"Last reported", host.LastReportedAt},
+				{"Apps", strings.Join(host.Apps, ", ")},
+				{"Sources", strings.Join(host.Sources, ", ")},
+				{"Aliases", strings.Join(host.Aliases, ", ")},
+				{"Tags", host.TagsBySource},
+			},
+		})
+	}
+	return entries, nil
+}
+
+// loadMonitors fetches the monitors tab
+func (a *app) loadMonitors() ([]entry, error) {
+	list, err := a.monitors.List("", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+	
+	entries := make([]entry, 0, len(list))
+	for _, monitor := range list {
+		id := strconv.FormatInt(monitor.ID, 10)
+		
+		var silenced []string
+		if scopes, ok := monitor.Options["silenced"].(map[string]int64); ok {
+			for _, scope := range monitors.SilencedScopes(scopes) {
+				silenced = append(silenced, scope.Scope+" until "+scope.Until)
+			}
+		}
+		
+		entries = append(entries, entry{
+			id:     id,
+			name:   monitor.Name,
+			cells:  []string{monitor.Status, monitor.Name, monitor.Type},
+			search: id + " " + monitor.Name + " " + strings.Join(monitor.Tags, " "),
+			detail: []field{
+				{"ID", id},
+				{"Name", monitor.Name},
+				{"Status", monitor.Status},
+				{"Type", monitor.Type},
+				{"Query", monitor.Query},
+				{"Tags", strings.Join(monitor.Tags, ", ")},
+				{"Muted", strings.Join(silenced, "\n")},
+				{"Message", monitor.Message},
+			},
+		})
+	}
+	return entries, nil
+}
+
+// dropLastRune removes the last character of s
+func dropLastRune(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	return string(runes[:len(runes)-1])
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testApp() *app {
+	a := newApp(nil, nil, nil, nil, "datadoghq.com", 0, nil)
+	var buf bytes.Buffer
+	a.out = &buf
+	a.handle(loadedEvent{tab: hostsTab, entries: []entry{
+		{id: "web-01", name: "web-01", cells: []string{"web-01", "true", ""}, search: "web-01 env:prod",
+			detail: []field{{"Name", "web-01"}, {"Tags", strings.Repeat("env:prod ", 30)}}},
+		{id: "db-01", name: "db-01", cells: []string{"db-01", "false", "muted"}, search: "db-01 env:prod"},
+		{id: "web-02", name: "web-02", cells: []string{"web-02", "true", ""}, search: "web-02 env:staging"},
+	}})
+	return a
+}
+
+func TestApp_Filter(t *testing.T) {
+	a := testApp()
+	hostsTab := a.tabs[hostsTab]
+
+	// Selection follows the entry when the filter changes
+	hostsTab.move(2)
+	for _, r := range "/web" {
+		a.handle(key{r: r})
+	}
+	if len(hostsTab.visible) != 2 {
+		t.Fatalf("visible = %d, want 2", len(hostsTab.visible))
+	}
+	if got := hostsTab.selectedID(); got != "web-02" {
+		t.Errorf("selected = %q, want web-02", got)
+	}
+
+	a.handle(key{name: "esc"})
+	if hostsTab.filter != "" || len(hostsTab.visible) != 3 {
+		t.Errorf("esc did not clear the filter: %q, %d visible", hostsTab.filter, len(hostsTab.visible))
+	}
+}
+
+func TestApp_Draw(t *testing.T) {
+	a := testApp()
+	a.draw()
+
+	out := a.out.(*bytes.Buffer).String()
+	lines := strings.Split(out, "\r\n")
+	if len(lines) != 24 {
+		t.Errorf("draw() wrote %d lines, want 24", len(lines))
+	}
+	for _, want := range []string{"1 Hosts (3)", "web-01", "db-01", "Tags"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("draw() output missing %q", want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	lines := wrap("alpha beta gamma\nsupercalifragilistic", 10)
+	want := []string{"alpha beta", "gamma", "supercalif", "ragilistic"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("wrap() = %q, want %q", lines, want)
+	}
+}
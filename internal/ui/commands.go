@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/padawandba/datadog-cli/internal/hosts"
+	"github.com/padawandba/datadog-cli/internal/monitors"
+	"github.com/padawandba/datadog-cli/internal/platform/config"
+	ddapi "github.com/padawandba/datadog-cli/internal/platform/datadog"
+	"github.com/padawandba/datadog-cli/internal/tags"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommands returns the ui command
+func NewCommands(apiClient *datadog.APIClient, ctx context.Context, cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "ui",
+		Usage: "Browse hosts and monitors in an interactive terminal UI",
+		Description: "Shows hosts and monitors in tabs with fuzzy filtering and a detail pane.\n" +
+			"Keys: tab/1/2 switch tabs, ↑/↓ or j/k move, / filter, esc clear filter,\n" +
+			"m mute, u unmute, a add host tags, d remove host tags, r refresh, q quit.",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "refresh",
+				Value: 30 * time.Second,
+				Usage: "Reload hosts and monitors at this interval",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// Warnings shown on the status line are redacted like every log
+			redactor, err := ddapi.NewRedactor(cfg)
+			if err != nil {
+				return err
+			}
+			
+			app := newApp(
+				c.Context,
+				hosts.NewClient(apiClient, ctx),
+				monitors.NewClient(apiClient, ctx),
+				tags.NewClient(apiClient, ctx),
+				cfg.Site,
+				c.Duration("refresh"),
+				redactor,
+			)
+			return app.run()
+		},
+	}
+}
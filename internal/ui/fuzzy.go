@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore reports whether every character of pattern appears in text in
+// order, ignoring case, and scores the match: consecutive characters and
+// characters at the start of words score higher. An empty pattern matches
+// everything with a score of 0.
+func fuzzyScore(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return 0, true
+	}
+	
+	target := []rune(strings.ToLower(text))
+	score := 0
+	pos := 0
+	previous := -2
+	
+	for _, p := range pattern {
+		if unicode.IsSpace(p) {
+			continue
+		}
+		
+		found := -1
+		for i := pos; i < len(target); i++ {
+			if target[i] == p {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+		
+		score++
+		if found == previous+1 {
+			score += 5
+		}
+		if found == 0 || !unicode.IsLetter(target[found-1]) && !unicode.IsDigit(target[found-1]) {
+			score += 3
+		}
+		
+		previous = found
+		pos = found + 1
+	}
+	
+	// Prefer shorter texts among equally good matches
+	return score*100 - len(target), true
+}
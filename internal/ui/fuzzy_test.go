@@ -0,0 +1,44 @@
+package ui
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"", "anything", true},
+		{"web", "web-01.prod", true},
+		{"wb1", "web-01.prod", true},
+		{"WEB", "web-01.prod", true},
+		{"bew", "web-01.prod", false},
+		{"db", "web-01.prod", false},
+	}
+
+	for _, tt := range tests {
+		if _, got := fuzzyScore(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+
+	// Consecutive and word-start matches rank above scattered ones
+	prefix, _ := fuzzyScore("api", "api-gateway")
+	scattered, _ := fuzzyScore("api", "a-proxy-internal")
+	if prefix <= scattered {
+		t.Errorf("fuzzyScore() prefix = %d, scattered = %d; want prefix to rank higher", prefix, scattered)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys([]byte("a\x1b[A\x1b[6~\r\x1b\x7fé"))
+	want := []key{{r: 'a'}, {name: "up"}, {name: "pgdown"}, {name: "enter"}, {name: "esc"}, {name: "backspace"}, {r: 'é'}}
+	if len(keys) != len(want) {
+		t.Fatalf("parseKeys() = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("parseKeys()[%d] = %v, want %v", i, keys[i], want[i])
+		}
+	}
+}
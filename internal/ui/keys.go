@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// key is a single key press read from the terminal
+type key struct {
+	// name identifies special keys ("up", "enter", "ctrl-c", ...); it is
+	// empty for printable characters
+	name string
+	// r is the character typed when name is empty
+	r rune
+}
+
+// escapeSequences maps terminal escape sequences to key names
+var escapeSequences = map[string]string{
+	"\x1b[A":  "up",
+	"\x1b[B":  "down",
+	"\x1b[C":  "right",
+	"\x1b[D":  "left",
+	"\x1bOA":  "up",
+	"\x1bOB":  "down",
+	"\x1bOC":  "right",
+	"\x1bOD":  "left",
+	"\x1b[H":  "home",
+	"\x1b[F":  "end",
+	"\x1b[1~": "home",
+	"\x1b[4~": "end",
+	"\x1b[5~": "pgup",
+	"\x1b[6~": "pgdown",
+	"\x1b[Z":  "shift-tab",
+}
+
+// parseKeys splits raw terminal input into key presses
+func parseKeys(input []byte) []key {
+	var keys []key
+	for len(input) > 0 {
+		switch c := input[0]; {
+		case c == 0x1b:
+			matched := false
+			for seq, name := range escapeSequences {
+				if len(input) >= len(seq) && string(input[:len(seq)]) == seq {
+					keys = append(keys, key{name: name})
+					input = input[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// A lone escape, or an unknown sequence that is dropped whole
+				if len(input) > 1 && (input[1] == '[' || input[1] == 'O') {
+					input = skipSequence(input)
+					continue
+				}
+				keys = append(keys, key{name: "esc"})
+				input = input[1:]
+			}
+		case c == '\r' || c == '\n':
+			keys = append(keys, key{name: "enter"})
+			input = input[1:]
+		case c == '\t':
+			keys = append(keys, key{name: "tab"})
+			input = input[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, key{name: "backspace"})
+			input = input[1:]
+		case c == 0x03:
+			keys = append(keys, key{name: "ctrl-c"})
+			input = input[1:]
+		case c == 0x15:
+			keys = append(keys, key{name: "ctrl-u"})
+			input = input[1:]
+		case c < 0x20:
+			// Other control characters are ignored
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, key{r: r})
+			input = input[size:]
+		}
+	}
+	return keys
+}
+
+// skipSequence drops an unrecognized escape sequence
+func skipSequence(input []byte) []byte {
+	for i := 2; i < len(input); i++ {
+		if input[i] >= 0x40 && input[i] <= 0x7e {
+			return input[i+1:]
+		}
+	}
+	return nil
+}
+
+// readKeys reads key presses from r and sends them as events until r fails
+func (a *app) readKeys(r io.Reader) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			a.events <- k
+		}
+	}
+}
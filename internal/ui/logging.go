@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+)
+
+// statusLogHandler passes every record on to the logging the UI was started
+// with and also shows warnings and errors on the status line. Local logs that
+// would be written to the terminal are held while the UI is open, since they
+// would corrupt the screen.
+type statusLogHandler struct {
+	next   slog.Handler
+	events chan<- interface{}
+}
+
+// logEvent carries a log record to the status line
+type logEvent struct {
+	message string
+}
+
+func (h statusLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || h.next.Enabled(ctx, level)
+}
+
+func (h statusLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn {
+		h.showStatus(record)
+	}
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// showStatus sends the message of a record, with its error if any, to the
+// status line
+func (h statusLogHandler) showStatus(record slog.Record) {
+	message := record.Message
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "error" {
+			message += ": " + attr.Value.String()
+			return false
+		}
+		return true
+	})
+	
+	// Never block the caller if the UI is busy
+	select {
+	case h.events <- logEvent{message: message}:
+	default:
+	}
+}
+
+func (h statusLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return statusLogHandler{next: h.next.WithAttrs(attrs), events: h.events}
+}
+
+func (h statusLogHandler) WithGroup(name string) slog.Handler {
+	return statusLogHandler{next: h.next.WithGroup(name), events: h.events}
+}
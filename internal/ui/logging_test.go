@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStatusLogHandler(t *testing.T) {
+	var out bytes.Buffer
+	events := make(chan interface{}, 4)
+	h := statusLogHandler{
+		next:   slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		events: events,
+	}
+	logger := slog.New(h).With("component", "ui")
+
+	logger.Info("Muted monitor")
+	logger.Warn("Failed to load hosts", "error", "timeout")
+
+	// Every record still reaches the previous logging, with bound attrs
+	for _, want := range []string{"msg=\"Muted monitor\" component=ui", "msg=\"Failed to load hosts\" component=ui error=timeout"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("next handler output = %q, want %q", out.String(), want)
+		}
+	}
+
+	// Only warnings reach the status line
+	if len(events) != 1 {
+		t.Fatalf("status line got %d events, want 1", len(events))
+	}
+	if ev := (<-events).(logEvent); ev.message != "Failed to load hosts: timeout" {
+		t.Errorf("status message = %q", ev.message)
+	}
+}
@@ -0,0 +1,301 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/padawandba/datadog-cli/internal/platform/console"
+)
+
+// Screen styles
+const (
+	styleReverse = "\x1b[7m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleRed     = "\x1b[31m"
+	styleReset   = "\x1b[0m"
+)
+
+// helpText lists the key bindings shown at the bottom of the screen
+const helpText = "↑/↓ move  tab switch  / filter  m mute  u unmute  a add tag  d remove tag  r refresh  q quit"
+
+// listHeight returns the number of list rows that fit on screen, excluding its header
+func (a *app) listHeight() int {
+	// Tab bar, filter line, list header, status line and help line
+	return max(a.height-5, 1)
+}
+
+// draw redraws the whole screen
+func (a *app) draw() {
+	a.width, a.height = console.TerminalSize(a.out)
+	if a.width <= 0 || a.height <= 0 {
+		a.width, a.height = 80, 24
+	}
+	
+	t := a.tabs[a.active]
+	listWidth := max(a.width*45/100, min(a.width, 30))
+	detailWidth := max(a.width-listWidth-3, 0)
+	
+	lines := make([]string, 0, a.height)
+	lines = append(lines, a.tabBar())
+	lines = append(lines, a.filterLine(t))
+	
+	list := a.listLines(t, listWidth)
+	detail := a.detailLines(t, detailWidth, len(list))
+	for i := range list {
+		line := list[i]
+		if detailWidth > 0 {
+			line += " " + styleDim + "│" + styleReset + " " + detail[i]
+		}
+		lines = append(lines, line)
+	}
+	
+	lines = append(lines, a.statusLine())
+	lines = append(lines, styleDim+console.TruncateDisplay(helpText, a.width)+styleReset)
+	
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			// Raw mode disables newline translation, so return the carriage explicitly
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	fmt.Fprint(a.out, b.String())
+}
+
+// tabBar renders the tab titles and refresh state
+func (a *app) tabBar() string {
+	var left strings.Builder
+	plain := 0
+	for i, t := range a.tabs {
+		label := fmt.Sprintf(" %d %s (%d) ", i+1, t.title, len(t.visible))
+		plain += console.DisplayWidth(label) + 1
+		if i == a.active {
+			label = styleReverse + styleBold + label + styleReset
+		}
+		left.WriteString(label + " ")
+	}
+	
+	t := a.tabs[a.active]
+	right := a.site
+	switch {
+	case t.loading:
+		right += "  loading..."
+	case !t.loadedAt.IsZero():
+		right += "  refreshed " + t.loadedAt.Format(time.TimeOnly)
+	}
+	
+	gap := a.width - plain - console.DisplayWidth(right)
+	if gap < 1 {
+		return left.String()
+	}
+	return left.String() + strings.Repeat(" ", gap) + styleDim + right + styleReset
+}
+
+// filterLine renders the active tab's filter
+func (a *app) filterLine(t *tab) string {
+	if a.mode == filterMode {
+		return "/" + t.filter + styleReverse + " " + styleReset
+	}
+	if t.filter != "" {
+		return styleDim + "filter: " + styleReset + t.filter + styleDim + "  (esc to clear)" + styleReset
+	}
+	return styleDim + "press / to filter" + styleReset
+}
+
+// listLines renders the list pane, exactly listHeight()+1 lines of width columns
+func (a *app) listLines(t *tab, width int) []string {
+	height := a.listHeight()
+	lines := make([]string, 0, height+1)
+	
+	// Keep the cursor on screen
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+height {
+		t.offset = t.cursor - height + 1
+	}
+	t.offset = max(0, min(t.offset, max(len(t.visible)-height, 0)))
+	
+	widths := columnWidths(t, width)
+	lines = append(lines, styleBold+pad(formatRow(t.headers, widths), width)+styleReset)
+	
+	switch {
+	case t.loadError != nil && len(t.entries) == 0:
+		lines = append(lines, styleRed+pad(t.loadError.Error(), width)+styleReset)
+	case t.loading && len(t.entries) == 0:
+		lines = append(lines, pad("Loading...", width))
+	case len(t.visible) == 0:
+		lines = append(lines, pad("No matches", width))
+	}
+	
+	for i := t.offset; i < len(t.visible) && len(lines) < height+1; i++ {
+		e := t.entries[t.visible[i]]
+		cells := make([]string, len(e.cells))
+		for j, cell := range e.cells {
+			cell = padRight(console.TruncateDisplay(cell, widths[j]), widths[j])
+			cells[j] = console.Colorize(t.headers[j], cell)
+		}
+		line := strings.Join(cells, " ")
+		if i == t.cursor {
+			line = styleReverse + line + styleReset
+		}
+		lines = append(lines, line)
+	}
+	
+	for len(lines) < height+1 {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+// detailLines renders the selected entry's fields, exactly count lines
+func (a *app) detailLines(t *tab, width int, count int) []string {
+	lines := make([]string, 0, count)
+	if e := t.selected(); e != nil && width > 0 {
+		labelWidth := 0
+		for _, f := range e.detail {
+			labelWidth = max(labelWidth, console.DisplayWidth(f.name))
+		}
+		valueWidth := max(width-labelWidth-2, 10)
+		
+		for _, f := range e.detail {
+			value := f.value
+			if value == "" {
+				value = "-"
+			}
+			for i, part := range wrap(value, valueWidth) {
+				label := ""
+				if i == 0 {
+					label = f.name
+				}
+				lines = append(lines, styleBold+padRight(label, labelWidth)+styleReset+"  "+part)
+			}
+		}
+	}
+	
+	if len(lines) > count {
+		lines = lines[:count]
+	}
+	for len(lines) < count {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// statusLine renders the prompt or the last status message
+func (a *app) statusLine() string {
+	if a.mode == promptMode {
+		return a.prompt + a.input + styleReverse + " " + styleReset
+	}
+	if a.statusError {
+		return styleRed + console.TruncateDisplay(a.status, a.width) + styleReset
+	}
+	return console.TruncateDisplay(a.status, a.width)
+}
+
+// columnWidths sizes the list columns to fit width, giving spare room to the
+// widest column
+func columnWidths(t *tab, width int) []int {
+	widths := make([]int, len(t.headers))
+	for i, header := range t.headers {
+		widths[i] = console.DisplayWidth(header)
+	}
+	for _, index := range t.visible {
+		for i, cell := range t.entries[index].cells {
+			widths[i] = max(widths[i], console.DisplayWidth(cell))
+		}
+	}
+	
+	// Shrink the widest column until the row fits
+	total := len(widths) - 1
+	for _, w := range widths {
+		total += w
+	}
+	for total > width {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 4 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// formatRow lays out plain cells in columns
+func formatRow(cells []string, widths []int) string {
+	parts := make([]string, len(cells))
+	for i, cell := range cells {
+		parts[i] = padRight(console.TruncateDisplay(cell, widths[i]), widths[i])
+	}
+	return strings.Join(parts, " ")
+}
+
+// pad truncates or pads plain text to exactly width columns
+func pad(s string, width int) string {
+	return padRight(console.TruncateDisplay(s, width), width)
+}
+
+// padRight pads s with spaces to width columns
+func padRight(s string, width int) string {
+	if w := console.DisplayWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// wrap splits text into lines of at most width columns, breaking at spaces
+// where possible and keeping existing line breaks
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			// Words longer than a line are split
+			for console.DisplayWidth(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				head, tail := splitAt(word, width)
+				lines = append(lines, head)
+				word = tail
+			}
+			
+			switch {
+			case line == "":
+				line = word
+			case console.DisplayWidth(line)+1+console.DisplayWidth(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitAt splits s after width display columns
+func splitAt(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := console.DisplayWidth(string(r))
+		if used+w > width {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}